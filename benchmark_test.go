@@ -154,3 +154,177 @@ func BenchmarkIsPrime(b *testing.B) {
 func BenchmarkBaselineIsPrime(b *testing.B) {
 	nprimes -= benchmarkIsPrime(b, baselineIsPrime)
 }
+
+func BenchmarkIsPrimeMR(b *testing.B) {
+	nprimes += benchmarkIsPrime(b, func(n int) bool {
+		return primes.IsPrimeMR(int64(n))
+	})
+}
+
+// benchmarkIsPrimeLarge tests b.N numbers close to 2^40, where trial division
+// already needs to check hundreds of thousands of candidate divisors
+// (numbers close to math.MaxInt64 would make the benchmark take forever).
+func benchmarkIsPrimeLarge(b *testing.B, isPrime func(n int64) bool) int {
+	nps := 0
+	for i := 0; i < b.N; i++ {
+		if isPrime(1<<40 + int64(i%1000)) {
+			nps++
+		}
+	}
+	return nps
+}
+
+func BenchmarkIsPrimeLarge(b *testing.B) {
+	nprimes += benchmarkIsPrimeLarge(b, func(n int64) bool {
+		return primes.IsPrime(int(n))
+	})
+}
+
+func BenchmarkIsPrimeMRLarge(b *testing.B) {
+	nprimes -= benchmarkIsPrimeLarge(b, primes.IsPrimeMR)
+}
+
+// benchmarkCount counts the primes up to b.N increasing values of n
+func benchmarkCount(b *testing.B, count func(n int) int) int {
+	pi := 0
+	for i := 0; i < b.N; i++ {
+		pi += count(100000 + 100*i)
+	}
+	return pi
+}
+
+// We expect a Counter to be much faster than PiExact for repeated queries
+// since it never sieves the same numbers twice
+func BenchmarkCounter(b *testing.B) {
+	var c primes.Counter
+	nprimes += benchmarkCount(b, c.Count)
+}
+
+func BenchmarkPiExact(b *testing.B) {
+	nprimes -= benchmarkCount(b, primes.PiExact)
+}
+
+func BenchmarkSieveParallel(b *testing.B) {
+	nprimes += benchmarkSieve(b, func(n int) []int {
+		return primes.SieveParallel(n, 0)
+	})
+}
+
+// benchmarkSieveLarge times sieve(10^9); it is skipped in short mode since
+// each run takes seconds and a couple of gigabytes of memory
+func benchmarkSieveLarge(b *testing.B, sieve func(n int) []int) int {
+	if testing.Short() {
+		b.Skip("skipping in short mode")
+	}
+	np := 0
+	for i := 0; i < b.N; i++ {
+		np += len(sieve(1000000000))
+	}
+	return np
+}
+
+func BenchmarkSieveLarge(b *testing.B) {
+	nprimes += benchmarkSieveLarge(b, primes.Sieve)
+}
+
+func BenchmarkSieveParallelLarge(b *testing.B) {
+	nprimes -= benchmarkSieveLarge(b, func(n int) []int {
+		return primes.SieveParallel(n, 0)
+	})
+}
+
+func BenchmarkSmallestPrimeFactorsSegment(b *testing.B) {
+	// Factor a band of 10^5 numbers around 10^9
+	const lo = 1000000000
+	for i := 0; i < b.N; i++ {
+		nprimes += len(primes.SmallestPrimeFactorsSegment(lo, lo+100000))
+	}
+}
+
+// benchmarkFactorizeAll times the factorization of all the numbers up to
+// 10^5
+func benchmarkFactorizeAll(b *testing.B, factorize func(m int) map[int]int) int {
+	nfs := 0
+	for i := 0; i < b.N; i++ {
+		for m := 2; m <= 100000; m++ {
+			nfs += len(factorize(m))
+		}
+	}
+	return nfs
+}
+
+func BenchmarkFactorizeMap(b *testing.B) {
+	nprimes += benchmarkFactorizeAll(b, primes.FactorizeMap)
+}
+
+func BenchmarkFactorizeWithSPF(b *testing.B) {
+	spf := primes.SPFSieve(100000)
+	b.ResetTimer()
+	nprimes -= benchmarkFactorizeAll(b, func(m int) map[int]int {
+		return primes.FactorizeWithSPF(spf, m)
+	})
+}
+
+// benchmarkContains times a million membership queries below 10^6
+func benchmarkContains(b *testing.B, contains func(x int) bool) int {
+	n := 0
+	for i := 0; i < b.N; i++ {
+		for x := 0; x < 1000000; x++ {
+			if contains(x) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func BenchmarkIsPrimeQueries(b *testing.B) {
+	nprimes += benchmarkContains(b, primes.IsPrime)
+}
+
+func BenchmarkPrimeSetContains(b *testing.B) {
+	s := primes.NewPrimeSet(1000000)
+	b.ResetTimer()
+	nprimes -= benchmarkContains(b, s.Contains)
+}
+
+// benchmarkSieveHuge times sieve(10^8)
+func benchmarkSieveHuge(b *testing.B, sieve func(n int) []int) int {
+	np := 0
+	for i := 0; i < b.N; i++ {
+		np += len(sieve(100000000))
+	}
+	return np
+}
+
+func BenchmarkSieveHuge(b *testing.B) {
+	nprimes += benchmarkSieveHuge(b, primes.Sieve)
+}
+
+func BenchmarkSieveWheelHuge(b *testing.B) {
+	nprimes -= benchmarkSieveHuge(b, primes.SieveWheel)
+}
+
+// benchmarkSieveRepeated times b.N sieves of 10^5 numbers, reporting the
+// allocations made by each
+func benchmarkSieveRepeated(b *testing.B, sieve func(n int) []int) int {
+	b.ReportAllocs()
+	np := 0
+	for i := 0; i < b.N; i++ {
+		np += len(sieve(100000))
+	}
+	return np
+}
+
+func BenchmarkSieveRepeated(b *testing.B) {
+	nprimes += benchmarkSieveRepeated(b, primes.Sieve)
+}
+
+// We expect SieveInto to save the allocation of the sieve table on each
+// call, which is larger than the list of primes it returns
+func BenchmarkSieveIntoRepeated(b *testing.B) {
+	buf := make([]bool, 100000/2)
+	nprimes -= benchmarkSieveRepeated(b, func(n int) []int {
+		return primes.SieveInto(n, buf)
+	})
+}
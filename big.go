@@ -0,0 +1,148 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+import (
+	"math"
+	"math/big"
+	"sync"
+)
+
+// DefaultBigRounds is the number of Miller-Rabin rounds with random bases
+// performed by IsPrimeBig
+const DefaultBigRounds = 20
+
+// bigScreenGroup is a group of consecutive cached primes whose product
+// fits in a uint64
+type bigScreenGroup struct {
+	ps      []int
+	product *big.Int
+}
+
+// bigScreen holds the cached primes split into groups; it is used to screen
+// big integers for small factors with one big division per group rather
+// than one per prime.
+// It is initialized on first use by bigScreenOnce.
+var (
+	bigScreen     []bigScreenGroup
+	bigScreenOnce sync.Once
+)
+
+// initBigScreen populates bigScreen
+func initBigScreen() {
+	primes, _ := cachedPrimes()
+	var ps []int
+	prod := uint64(1)
+	for _, p := range primes {
+		if p > defaultCacheLimit {
+			// Screening with the primes of a larger cache would take
+			// longer than it saves, and the screen must not include primes
+			// that a later, smaller cache would leave out
+			break
+		}
+		if prod > math.MaxUint64/uint64(p) {
+			bigScreen = append(bigScreen, bigScreenGroup{ps, new(big.Int).SetUint64(prod)})
+			ps, prod = nil, 1
+		}
+		ps = append(ps, p)
+		prod *= uint64(p)
+	}
+	bigScreen = append(bigScreen, bigScreenGroup{ps, new(big.Int).SetUint64(prod)})
+}
+
+// IsPrimeBig is a primality test for arbitrarily large integers: it returns
+// true if n is prime (with a small probability of error, see below).
+// It is equivalent to IsPrimeBigRounds(n,DefaultBigRounds).
+func IsPrimeBig(n *big.Int) bool {
+	return IsPrimeBigRounds(n, DefaultBigRounds)
+}
+
+// IsPrimeBigRounds is a primality test for arbitrarily large integers:
+// it returns true if n is prime.
+// If n is small enough, it is looked up in the cache of primes; otherwise,
+// it is first checked for divisibility by the cached primes and only if that
+// test is inconclusive it is passed on to n.ProbablyPrime(rounds), which
+// performs the given number of Miller-Rabin rounds with random bases followed
+// by a Baillie-PSW test.
+// The result is always correct for n < 2^64; for larger n, the probability
+// of a composite passing the test is at most 1/4^rounds, and no such
+// composite is known to pass the Baillie-PSW test anyway.
+// See https://golang.org/pkg/math/big/#Int.ProbablyPrime for details.
+func IsPrimeBigRounds(n *big.Int, rounds int) bool {
+	if n.Sign() <= 0 {
+		return false
+	}
+	if primes, _ := cachedPrimes(); n.IsInt64() && n.Int64() <= int64(primes[len(primes)-1]) {
+		return IsPrime(int(n.Int64()))
+	}
+	// Check if n is divisible by any of the cached primes
+	bigScreenOnce.Do(initBigScreen)
+	r := new(big.Int)
+	for _, g := range bigScreen {
+		m := r.Mod(n, g.product).Uint64()
+		for _, p := range g.ps {
+			if m%uint64(p) == 0 {
+				return false
+			}
+		}
+	}
+	if rounds < 0 {
+		rounds = 0
+	}
+	return n.ProbablyPrime(rounds)
+}
+
+// maxPyramidBits is the size in bits of the largest power tower evaluated
+// by IsPrimePyramid
+const maxPyramidBits = 1 << 14
+
+// IsPrimePyramid evaluates the power tower base^base^...^base with height
+// copies of base (evaluated from the top down, so that 2^2^2^2 == 2^16) and
+// tests it for primality with IsPrimeBig.
+// A tower of height 0 is the empty product 1 and a tower of height 1 is
+// base itself; any higher tower is a perfect power and thus composite, but
+// the value is still useful for exploring numbers like 2^2^2^2+1.
+// Power towers grow so fast that only a handful of them can be written
+// down at all: if the value would have more than maxPyramidBits bits (as
+// does 2^2^2^2^2, with 65537 bits), IsPrimePyramid returns a nil value.
+// It also returns a nil value if base is less than 2 or height is negative.
+// See https://en.wikipedia.org/wiki/Tetration for details.
+func IsPrimePyramid(base, height int) (value *big.Int, prime bool) {
+	if base < 2 || height < 0 {
+		return nil, false
+	}
+	if height == 0 {
+		return big.NewInt(1), false
+	}
+	b := big.NewInt(int64(base))
+	value = new(big.Int).Set(b)
+	for i := 1; i < height; i++ {
+		// base^value has about value*log2(base) bits
+		if value.BitLen() > 32 ||
+			float64(value.Int64())*math.Log2(float64(base)) > maxPyramidBits {
+			return nil, false
+		}
+		value.Exp(b, value, nil)
+	}
+	return value, IsPrimeBig(value)
+}
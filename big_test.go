@@ -0,0 +1,130 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestIsPrimeBig(t *testing.T) {
+	// mersenne returns 2^p-1
+	mersenne := func(p uint) *big.Int {
+		m := new(big.Int).Lsh(big.NewInt(1), p)
+		return m.Sub(m, big.NewInt(1))
+	}
+
+	// 2^p-1 is prime for these p (see https://oeis.org/A000043)...
+	for _, p := range []uint{2, 3, 5, 7, 13, 17, 19, 31, 61, 89, 107, 127, 521, 607, 1279} {
+		if m := mersenne(p); !primes.IsPrimeBig(m) {
+			t.Errorf("IsPrimeBig(2^%d-1) == false, want true", p)
+		}
+	}
+	// ...but not for these
+	for _, p := range []uint{4, 11, 23, 29, 37, 67, 101, 257, 1277} {
+		if m := mersenne(p); primes.IsPrimeBig(m) {
+			t.Errorf("IsPrimeBig(2^%d-1) == true, want false", p)
+		}
+	}
+
+	// The product of two large primes is not prime
+	p := mersenne(127)
+	q := mersenne(89)
+	if n := new(big.Int).Mul(p, q); primes.IsPrimeBig(n) {
+		t.Errorf("IsPrimeBig(%v) == true, want false", n)
+	}
+	// Neither is a large number with a small factor
+	if n := new(big.Int).Mul(p, big.NewInt(9973)); primes.IsPrimeBig(n) {
+		t.Errorf("IsPrimeBig(%v) == true, want false", n)
+	}
+
+	// Check small numbers against IsPrime
+	for n := -10; n < 100000; n++ {
+		if got, want := primes.IsPrimeBig(big.NewInt(int64(n))), primes.IsPrime(n); got != want {
+			t.Errorf("IsPrimeBig(%d) == %v, want %v", n, got, want)
+		}
+	}
+	for _, ps := range contiguousPrimes {
+		for _, p := range ps {
+			if !primes.IsPrimeBigRounds(big.NewInt(int64(p)), 0) {
+				t.Errorf("IsPrimeBigRounds(%d,0) == false, want true", p)
+			}
+		}
+	}
+}
+
+func TestIsPrimePyramid(t *testing.T) {
+	cases := []struct {
+		base, height int
+		want         string
+		prime        bool
+	}{
+		{2, 0, "1", false},
+		{2, 1, "2", true},
+		{9, 1, "9", false},
+		{9973, 1, "9973", true},
+		{2, 2, "4", false},
+		{2, 3, "16", false},
+		{2, 4, "65536", false},
+		{3, 2, "27", false},
+		{3, 3, "7625597484987", false},
+		{4, 3, new(big.Int).Lsh(big.NewInt(1), 512).String(), false},
+	}
+	for _, c := range cases {
+		v, prime := primes.IsPrimePyramid(c.base, c.height)
+		if v == nil || v.String() != c.want || prime != c.prime {
+			t.Errorf("IsPrimePyramid(%d,%d) == (%v,%v), want (%s,%v)",
+				c.base, c.height, v, prime, c.want, c.prime)
+		}
+	}
+
+	// 2^2^...^2+1 is a Fermat number; it is prime for towers of height up
+	// to 4 (see https://oeis.org/A014221)
+	for height := 1; height <= 4; height++ {
+		v, _ := primes.IsPrimePyramid(2, height)
+		if f := new(big.Int).Add(v, big.NewInt(1)); !primes.IsPrimeBig(f) {
+			t.Errorf("IsPrimePyramid(2,%d)+1 == %v is not prime", height, f)
+		}
+	}
+
+	// Invalid arguments and towers that are too large give a nil value
+	invalid := []struct {
+		base, height int
+	}{
+		{0, 2},
+		{1, 2},
+		{2, -1},
+		{2, 5},
+		{3, 4},
+		{10, 3},
+		{1 << 20, 2},
+	}
+	for _, c := range invalid {
+		if v, prime := primes.IsPrimePyramid(c.base, c.height); v != nil || prime {
+			t.Errorf("IsPrimePyramid(%d,%d) == (%v,%v), want (nil,false)",
+				c.base, c.height, v, prime)
+		}
+	}
+}
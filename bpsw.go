@@ -0,0 +1,133 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// IsPrimeBPSW is a primality test for any int64: it returns true if n is
+// prime.
+// It implements the Baillie-PSW test, which combines a Miller-Rabin test
+// to base 2 (see IsPrimeMR) with a strong Lucas probable prime test whose
+// parameters are chosen with Selfridge's method. The two tests are fooled by
+// very different composites and no composite is known to pass both; in
+// fact, none exists below 2^64, so the result is exact for every int64.
+// Like IsPrimeMR, its running time grows with log(n) only, and small values
+// of n are looked up in the cached table of primes.
+// See https://en.wikipedia.org/wiki/Baillie%E2%80%93PSW_primality_test
+// for details.
+func IsPrimeBPSW(n int64) bool {
+	switch {
+	case n < 2:
+		return false
+	case n%2 == 0:
+		return n == 2
+	}
+	primes, _ := cachedPrimes()
+	if pMax := primes[len(primes)-1]; n <= int64(pMax) {
+		// If n is prime, it must be in the cache
+		i := sort.SearchInts(primes, int(n))
+		return int(n) == primes[i]
+	}
+	// Write n-1 as d*2^s with d odd
+	m := uint64(n)
+	s := bits.TrailingZeros64(m - 1)
+	d := (m - 1) >> uint(s)
+	return strongProbablePrime(m, 2, d, s) && strongLucasProbablePrime(n)
+}
+
+// strongLucasProbablePrime returns true if the odd number n > 10^4 is a strong
+// Lucas probable prime with the parameters chosen by Selfridge's method A:
+// D is the first of 5, -7, 9, -11, ... such that the Jacobi symbol (D/n)
+// is -1, P == 1, and Q == (1-D)/4.
+// See R. Baillie and S. S. Wagstaff, Jr., "Lucas Pseudoprimes",
+// Mathematics of Computation 35 (1980), pp. 1391-1417.
+func strongLucasProbablePrime(n int64) bool {
+	// No D exists if n is a perfect square, which is then composite
+	if r := isqrt64(n); r*r == n {
+		return false
+	}
+	d := int64(5)
+	for j := jacobi64(d, n); j != -1; j = jacobi64(d, n) {
+		if j == 0 {
+			// d shares a factor with n, which is much larger than |d|
+			return false
+		}
+		if d > 0 {
+			d = -d - 2
+		} else {
+			d = -d + 2
+		}
+	}
+	m := uint64(n)
+	// toMod reduces x to [0,n)
+	toMod := func(x int64) uint64 {
+		if x %= n; x < 0 {
+			x += n
+		}
+		return uint64(x)
+	}
+	// half returns x/2 modulo n, for x in [0,n)
+	half := func(x uint64) uint64 {
+		if x%2 == 1 {
+			// x+n is even and does not overflow since n < 2^63
+			x += m
+		}
+		return x / 2
+	}
+	sub := func(x, y uint64) uint64 {
+		return (x + m - y) % m
+	}
+	dm, qm := toMod(d), toMod((1-d)/4)
+	// Write n+1 as k*2^s with k odd
+	s := bits.TrailingZeros64(m + 1)
+	k := (m + 1) >> uint(s)
+	// Compute U_k, V_k, and Q^k modulo n scanning the bits of k from the
+	// top, starting with U_1 == 1, V_1 == P == 1, Q^1 == Q
+	u, v, qk := uint64(1), uint64(1), qm
+	for i := bits.Len64(k) - 2; i >= 0; i-- {
+		// U_2j == U_j*V_j, V_2j == V_j^2 - 2*Q^j
+		u = mulMod64(u, v, m)
+		v = sub(mulMod64(v, v, m), (2*qk)%m)
+		qk = mulMod64(qk, qk, m)
+		if k>>uint(i)&1 == 1 {
+			// U_2j+1 == (P*U_2j + V_2j)/2, V_2j+1 == (D*U_2j + P*V_2j)/2
+			u, v = half((u+v)%m), half((mulMod64(dm, u, m)+v)%m)
+			qk = mulMod64(qk, qm, m)
+		}
+	}
+	if u == 0 || v == 0 {
+		return true
+	}
+	// Check V_(k*2^r) for r in [1,s)
+	for r := 1; r < s; r++ {
+		v = sub(mulMod64(v, v, m), (2*qk)%m)
+		if v == 0 {
+			return true
+		}
+		qk = mulMod64(qk, qk, m)
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestIsPrimeBPSW(t *testing.T) {
+	cases := []struct {
+		n    int64
+		want bool
+	}{
+		{-7, false},
+		{0, false},
+		{1, false},
+		{2, true},
+		{3, true},
+		{4, false},
+		{9973, true},
+		{10007, true},
+		{10007 * 10007, false},
+		{1000003, true},
+		{math.MaxInt32, true},
+		{1<<59 - 1, false},
+		{1<<61 - 1, true},
+		{999999937 * 1000000007, false},
+		{9223372036854775783, true},
+		{math.MaxInt64, false},
+		// Strong pseudoprimes to several bases (see https://oeis.org/A014233)
+		{3215031751, false},
+		{3825123056546413051, false},
+		// Large Carmichael numbers
+		{2199733160881, false},
+		{9585921133193329, false},
+	}
+	for _, c := range cases {
+		if p := primes.IsPrimeBPSW(c.n); p != c.want {
+			t.Errorf("IsPrimeBPSW(%d) == %v, want %v", c.n, p, c.want)
+		}
+	}
+
+	// The strong pseudoprimes to base 2 pass the Miller-Rabin part of the
+	// test and must be caught by the Lucas part (see https://oeis.org/A001262)
+	for _, n := range []int64{
+		2047, 3277, 4033, 4681, 8321, 15841, 29341, 42799, 49141, 52633,
+		65281, 74665, 80581, 85489, 88357, 90751, 104653, 130561, 196093,
+	} {
+		if primes.IsPrimeBPSW(n) {
+			t.Errorf("IsPrimeBPSW(%d) == true, want false", n)
+		}
+	}
+	// Strong Lucas pseudoprimes are caught by the Miller-Rabin part
+	// (see https://oeis.org/A217255)
+	for _, n := range []int64{5459, 5777, 10877, 16109, 18971, 22499, 24569, 25199, 40309, 58519} {
+		if primes.IsPrimeBPSW(n) {
+			t.Errorf("IsPrimeBPSW(%d) == true, want false", n)
+		}
+	}
+	for _, n := range primes.CarmichaelNumbers(1000000) {
+		if primes.IsPrimeBPSW(int64(n)) {
+			t.Errorf("IsPrimeBPSW(%d) == true, want false", n)
+		}
+	}
+
+	// Check against the primes generated by Sieve and against IsPrimeMR
+	ps := primes.Sieve(1000000)
+	for n, i := int64(0), 0; n <= 1000000; n++ {
+		want := i < len(ps) && int64(ps[i]) == n
+		if want {
+			i++
+		}
+		if p := primes.IsPrimeBPSW(n); p != want {
+			t.Errorf("IsPrimeBPSW(%d) == %v, want %v", n, p, want)
+		}
+	}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 20000; i++ {
+		n := rng.Int63() | 1
+		if p, want := primes.IsPrimeBPSW(n), primes.IsPrimeMR(n); p != want {
+			t.Errorf("IsPrimeBPSW(%d) == %v, want %v", n, p, want)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+import (
+	"iter"
+	"math"
+)
+
+// constellations returns the prime constellations matching any of the given
+// patterns whose largest member is less than or equal to n.
+// A pattern is a list of increasing offsets starting at 0 and a constellation
+// matches it if p+offset is prime for every offset in the pattern.
+// Constellations are listed in increasing order of their smallest member p
+// and, for the same p, in the order their patterns are given.
+func constellations(n int, patterns ...[]int) [][]int {
+	s := NewPrimeSet(n)
+	cs := [][]int{}
+	for _, p := range s.Slice() {
+	patterns:
+		for _, pattern := range patterns {
+			if p+pattern[len(pattern)-1] > n {
+				continue
+			}
+			for _, d := range pattern[1:] {
+				if !s.Contains(p + d) {
+					continue patterns
+				}
+			}
+			c := make([]int, len(pattern))
+			for i, d := range pattern {
+				c[i] = p + d
+			}
+			cs = append(cs, c)
+		}
+	}
+	return cs
+}
+
+// TwinPrimes returns the pairs of twin primes (p,p+2) such that p+2 <= n,
+// in increasing order; for example, TwinPrimes(13) returns
+// [[3 5] [5 7] [11 13]].
+// The pairs are found by scanning the output of Sieve(n) for consecutive
+// primes that differ by 2.
+// If n is less than 5, it returns an empty list.
+// See https://en.wikipedia.org/wiki/Twin_prime for details.
+func TwinPrimes(n int) [][2]int {
+	ts := [][2]int{}
+	ps := Sieve(n)
+	for i := 1; i < len(ps); i++ {
+		if ps[i]-ps[i-1] == 2 {
+			ts = append(ts, [2]int{ps[i-1], ps[i]})
+		}
+	}
+	return ts
+}
+
+// TwinPrimesSeq returns an iterator over all the pairs of twin primes
+// (p,p+2), in increasing order and without any upper bound other than the
+// range of an int; the loop over it must be ended with a break:
+//
+//	for tp := range primes.TwinPrimesSeq() {
+//		if tp[0] > 1000 {
+//			break
+//		}
+//		...
+//	}
+//
+// It is like TwinPrimesFrom(2).
+func TwinPrimesSeq() iter.Seq[[2]int] {
+	return TwinPrimesFrom(2)
+}
+
+// TwinPrimesFrom returns an iterator over the pairs of twin primes (p,p+2)
+// such that p >= lo, in increasing order and without any upper bound other
+// than the range of an int; the loop over it must be ended with a break.
+// The pairs are found by scanning the output of AllFrom(lo) for consecutive
+// primes that differ by 2, so the primes are sieved in windows as the loop
+// goes on, starting from lo rather than from 2.
+// See https://en.wikipedia.org/wiki/Twin_prime for details.
+func TwinPrimesFrom(lo int) iter.Seq[[2]int] {
+	return func(yield func([2]int) bool) {
+		prev := 0
+		for p := range AllFrom(lo) {
+			if prev > 0 && p-prev == 2 && !yield([2]int{prev, p}) {
+				return
+			}
+			prev = p
+		}
+	}
+}
+
+// NearestTwinPrime returns the pair of twin primes (p,p+2) whose midpoint
+// p+1 is closest to n; if two pairs are equally close, it returns the
+// smaller one. For example, NearestTwinPrime(100) returns (101,103).
+// It searches outward from n, one candidate midpoint at a time on either
+// side, and tests the candidates with IsPrime; apart from 4, the midpoint
+// of (3,5), every midpoint is a multiple of 6, so only those are tried.
+// For n <= 4, it returns (3,5).
+// See https://en.wikipedia.org/wiki/Twin_prime for details.
+func NearestTwinPrime(n int) (p, q int) {
+	if n <= 4 {
+		return 3, 5
+	}
+	isMidpoint := func(m int) bool {
+		return m == 4 || m%6 == 0 && IsPrime(m-1) && IsPrime(m+1)
+	}
+	for d := 0; ; d++ {
+		if m := n - d; isMidpoint(m) {
+			return m - 1, m + 1
+		}
+		if m := n + d; isMidpoint(m) {
+			return m - 1, m + 1
+		}
+	}
+}
+
+// PrimeTriplets returns the prime triplets whose largest member is less than
+// or equal to n, in increasing order.
+// A prime triplet is a set of three primes of the form (p,p+2,p+6) or
+// (p,p+4,p+6); these are the only admissible patterns of three primes as
+// close together as possible, apart from the special cases (2,3,5) and (3,5,7).
+// See https://en.wikipedia.org/wiki/Prime_triplet for details.
+func PrimeTriplets(n int) [][3]int {
+	cs := constellations(n, []int{0, 2, 6}, []int{0, 4, 6})
+	ts := make([][3]int, len(cs))
+	for i, c := range cs {
+		copy(ts[i][:], c)
+	}
+	return ts
+}
+
+// IsSophieGermain returns true if p is a Sophie Germain prime, that is a
+// prime such that 2p+1 is prime too; for example, 11 is a Sophie Germain
+// prime since 23 is prime, while 7 is not since 15 is composite.
+// Both p and 2p+1 are tested with IsPrime.
+// If 2p+1 does not fit in an int, it returns false.
+// See https://en.wikipedia.org/wiki/Safe_and_Sophie_Germain_primes for
+// details.
+func IsSophieGermain(p int) bool {
+	if p > (math.MaxInt-1)/2 {
+		return false
+	}
+	return IsPrime(p) && IsPrime(2*p+1)
+}
+
+// SophieGermainPrimes returns the Sophie Germain primes less than or equal
+// to limit in increasing order (see IsSophieGermain); for example,
+// SophieGermainPrimes(50) returns [2 3 5 11 23 29 41].
+// The primes up to 2*limit+1 are sieved once into a PrimeSet, so that both
+// p and 2p+1 are simply looked up.
+// See https://oeis.org/A005384 for details.
+func SophieGermainPrimes(limit int) []int {
+	sg := []int{}
+	if limit < 2 {
+		return sg
+	}
+	s := NewPrimeSet(2*limit + 1)
+	for _, p := range s.Slice() {
+		if p > limit {
+			break
+		}
+		if s.Contains(2*p + 1) {
+			sg = append(sg, p)
+		}
+	}
+	return sg
+}
+
+// IsSafePrime returns true if p is a safe prime, that is a prime such that
+// (p-1)/2 is prime too, or equivalently p == 2q+1 for a Sophie Germain
+// prime q; for example, 23 is a safe prime since 11 is prime, while 13 is
+// not since 6 is composite.
+// Safe primes make good moduli for Diffie-Hellman key exchange, since the
+// multiplicative group mod p then has a large subgroup of prime order q.
+// Both p and (p-1)/2 are tested with IsPrime.
+// See https://en.wikipedia.org/wiki/Safe_and_Sophie_Germain_primes for
+// details.
+func IsSafePrime(p int) bool {
+	return p%2 == 1 && IsPrime(p) && IsPrime((p-1)/2)
+}
+
+// SafePrimes returns the safe primes less than or equal to limit in
+// increasing order (see IsSafePrime); for example, SafePrimes(100) returns
+// [5 7 11 23 47 59 83].
+// The primes up to limit are sieved once into a PrimeSet, so that both p
+// and (p-1)/2 are simply looked up.
+// See https://oeis.org/A005385 for details.
+func SafePrimes(limit int) []int {
+	sp := []int{}
+	s := NewPrimeSet(limit)
+	for _, p := range s.Slice() {
+		if p > 2 && s.Contains((p-1)/2) {
+			sp = append(sp, p)
+		}
+	}
+	return sp
+}
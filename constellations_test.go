@@ -0,0 +1,316 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestTwinPrimes(t *testing.T) {
+	cases := []struct {
+		n    int
+		want [][2]int
+	}{
+		{-1, [][2]int{}},
+		{0, [][2]int{}},
+		{4, [][2]int{}},
+		{5, [][2]int{{3, 5}}},
+		{6, [][2]int{{3, 5}}},
+		{7, [][2]int{{3, 5}, {5, 7}}},
+		{13, [][2]int{{3, 5}, {5, 7}, {11, 13}}},
+	}
+	for _, c := range cases {
+		ts := primes.TwinPrimes(c.n)
+		if !reflect.DeepEqual(ts, c.want) {
+			t.Errorf("TwinPrimes(%d) == %v, want %v", c.n, ts, c.want)
+		}
+	}
+
+	counts := []struct {
+		n    int
+		want int
+	}{
+		{100, 8},
+		{1000, 35},
+		{10000, 205},
+		{100000, 1224},
+		{1000000, 8169},
+	}
+	for _, c := range counts {
+		ts := primes.TwinPrimes(c.n)
+		if len(ts) != c.want {
+			t.Errorf("|TwinPrimes(%d)| == %d, want %d", c.n, len(ts), c.want)
+		}
+		for _, tp := range ts {
+			if !primes.IsPrime(tp[0]) || tp[1] != tp[0]+2 || !primes.IsPrime(tp[1]) || tp[1] > c.n {
+				t.Errorf("TwinPrimes(%d) contains %v", c.n, tp)
+			}
+		}
+	}
+}
+
+func TestTwinPrimesSeq(t *testing.T) {
+	// See https://oeis.org/A077800
+	want := [][2]int{
+		{3, 5}, {5, 7}, {11, 13}, {17, 19}, {29, 31},
+		{41, 43}, {59, 61}, {71, 73}, {101, 103}, {107, 109},
+	}
+	ts := [][2]int{}
+	for tp := range primes.TwinPrimesSeq() {
+		ts = append(ts, tp)
+		if len(ts) == len(want) {
+			break
+		}
+	}
+	if !reflect.DeepEqual(ts, want) {
+		t.Errorf("TwinPrimesSeq() yields %v, want %v", ts, want)
+	}
+
+	// Check against TwinPrimes across many windows
+	ts = [][2]int{}
+	for tp := range primes.TwinPrimesSeq() {
+		if tp[1] > 1000000 {
+			break
+		}
+		ts = append(ts, tp)
+	}
+	if want := primes.TwinPrimes(1000000); !reflect.DeepEqual(ts, want) {
+		t.Errorf("TwinPrimesSeq() yields %d pairs up to 1000000, want %d", len(ts), len(want))
+	}
+}
+
+func TestTwinPrimesFrom(t *testing.T) {
+	cases := []struct {
+		lo   int
+		want [][2]int
+	}{
+		{-10, [][2]int{{3, 5}, {5, 7}, {11, 13}}},
+		{4, [][2]int{{5, 7}, {11, 13}, {17, 19}}},
+		{5, [][2]int{{5, 7}, {11, 13}, {17, 19}}},
+		{6, [][2]int{{11, 13}, {17, 19}, {29, 31}}},
+		{1000000, [][2]int{{1000037, 1000039}, {1000211, 1000213}, {1000289, 1000291}}},
+	}
+	for _, c := range cases {
+		ts := [][2]int{}
+		for tp := range primes.TwinPrimesFrom(c.lo) {
+			ts = append(ts, tp)
+			if len(ts) == len(c.want) {
+				break
+			}
+		}
+		if !reflect.DeepEqual(ts, c.want) {
+			t.Errorf("TwinPrimesFrom(%d) yields %v, want %v", c.lo, ts, c.want)
+		}
+	}
+}
+
+func TestNearestTwinPrime(t *testing.T) {
+	cases := []struct {
+		n    int
+		p, q int
+	}{
+		{-10, 3, 5},
+		{0, 3, 5},
+		{4, 3, 5},
+		// Ties go to the smaller pair: 5 is as far from 4 as from 6
+		{5, 3, 5},
+		{6, 5, 7},
+		{9, 5, 7},
+		{10, 11, 13},
+		{15, 11, 13},
+		{16, 17, 19},
+		{100, 101, 103},
+		{1000000, 1000037, 1000039},
+	}
+	for _, c := range cases {
+		if p, q := primes.NearestTwinPrime(c.n); p != c.p || q != c.q {
+			t.Errorf("NearestTwinPrime(%d) == (%d,%d), want (%d,%d)", c.n, p, q, c.p, c.q)
+		}
+	}
+
+	// Check against the list of twin primes
+	ts := primes.TwinPrimes(20000)
+	for n := 0; n <= 15000; n++ {
+		want := ts[0]
+		for _, tp := range ts[1:] {
+			if d, dw := tp[0]+1-n, want[0]+1-n; d*d < dw*dw {
+				want = tp
+			}
+		}
+		if p, q := primes.NearestTwinPrime(n); p != want[0] || q != want[1] {
+			t.Errorf("NearestTwinPrime(%d) == (%d,%d), want (%d,%d)", n, p, q, want[0], want[1])
+		}
+	}
+}
+
+func TestPrimeTriplets(t *testing.T) {
+	want := [][3]int{
+		{5, 7, 11}, {7, 11, 13}, {11, 13, 17}, {13, 17, 19}, {17, 19, 23},
+		{37, 41, 43}, {41, 43, 47}, {67, 71, 73}, {97, 101, 103},
+		{101, 103, 107}, {103, 107, 109}, {107, 109, 113},
+	}
+	if ts := primes.PrimeTriplets(113); !reflect.DeepEqual(ts, want) {
+		t.Errorf("PrimeTriplets(113) == %v, want %v", ts, want)
+	}
+	for _, n := range []int{-1, 0, 7, 10} {
+		if ts := primes.PrimeTriplets(n); len(ts) != 0 {
+			t.Errorf("PrimeTriplets(%d) == %v, want []", n, ts)
+		}
+	}
+
+	// Check that every triplet matches one of the two patterns and that
+	// no triplet is missing
+	const max = 100000
+	isPrime := make([]bool, max+1)
+	for _, p := range baselineSieve(max) {
+		isPrime[p] = true
+	}
+	ts := primes.PrimeTriplets(max)
+	i := 0
+	for p := 2; p+6 <= max; p++ {
+		if !isPrime[p] || !isPrime[p+6] {
+			continue
+		}
+		for _, d := range []int{2, 4} {
+			if !isPrime[p+d] {
+				continue
+			}
+			w := [3]int{p, p + d, p + 6}
+			if i >= len(ts) {
+				t.Fatalf("PrimeTriplets(%d) is missing %v", max, w)
+			}
+			if ts[i] != w {
+				t.Fatalf("PrimeTriplets(%d)[%d] == %v, want %v", max, i, ts[i], w)
+			}
+			i++
+		}
+	}
+	if i != len(ts) {
+		t.Errorf("|PrimeTriplets(%d)| == %d, want %d", max, len(ts), i)
+	}
+}
+
+func TestIsSophieGermain(t *testing.T) {
+	cases := []struct {
+		p    int
+		want bool
+	}{
+		{-5, false},
+		{0, false},
+		{1, false},
+		{2, true},
+		{3, true},
+		{4, false},
+		{5, true},
+		{7, false},
+		{11, true},
+		{13, false},
+		{23, true},
+		{1000003, false},
+		{1000151, true},
+	}
+	for _, c := range cases {
+		if sg := primes.IsSophieGermain(c.p); sg != c.want {
+			t.Errorf("IsSophieGermain(%d) == %v, want %v", c.p, sg, c.want)
+		}
+	}
+
+	// See https://oeis.org/A005384
+	want := []int{2, 3, 5, 11, 23, 29, 41, 53, 83, 89, 113, 131, 173, 179, 191, 233}
+	if sg := primes.SophieGermainPrimes(233); !reflect.DeepEqual(sg, want) {
+		t.Errorf("SophieGermainPrimes(233) == %v, want %v", sg, want)
+	}
+	for _, limit := range []int{-1, 0, 1} {
+		if sg := primes.SophieGermainPrimes(limit); len(sg) != 0 {
+			t.Errorf("SophieGermainPrimes(%d) == %v, want []", limit, sg)
+		}
+	}
+
+	// Check against IsSophieGermain
+	sg := primes.SophieGermainPrimes(100000)
+	i := 0
+	for p := 0; p <= 100000; p++ {
+		if !primes.IsSophieGermain(p) {
+			continue
+		}
+		if i >= len(sg) || sg[i] != p {
+			t.Fatalf("SophieGermainPrimes(100000) is missing %d", p)
+		}
+		i++
+	}
+	if i != len(sg) {
+		t.Errorf("|SophieGermainPrimes(100000)| == %d, want %d", len(sg), i)
+	}
+}
+
+func TestIsSafePrime(t *testing.T) {
+	cases := []struct {
+		p    int
+		want bool
+	}{
+		{-7, false},
+		{0, false},
+		{2, false},
+		{3, false},
+		{5, true},
+		{7, true},
+		{9, false},
+		{11, true},
+		{13, false},
+		{23, true},
+		{2000303, true},
+		{2000423, true},
+		{2000425, false},
+	}
+	for _, c := range cases {
+		if sp := primes.IsSafePrime(c.p); sp != c.want {
+			t.Errorf("IsSafePrime(%d) == %v, want %v", c.p, sp, c.want)
+		}
+	}
+
+	// See https://oeis.org/A005385
+	want := []int{5, 7, 11, 23, 47, 59, 83, 107, 167, 179, 227, 263, 347, 359, 383, 467, 479}
+	if sp := primes.SafePrimes(479); !reflect.DeepEqual(sp, want) {
+		t.Errorf("SafePrimes(479) == %v, want %v", sp, want)
+	}
+	for _, limit := range []int{-1, 0, 4} {
+		if sp := primes.SafePrimes(limit); len(sp) != 0 {
+			t.Errorf("SafePrimes(%d) == %v, want []", limit, sp)
+		}
+	}
+
+	// Check that the safe primes are 2q+1 for the Sophie Germain primes q
+	sp := primes.SafePrimes(200001)
+	sg := primes.SophieGermainPrimes(100000)
+	if len(sp) != len(sg) {
+		t.Fatalf("|SafePrimes(200001)| == %d, want %d", len(sp), len(sg))
+	}
+	for i, q := range sg {
+		if sp[i] != 2*q+1 {
+			t.Fatalf("SafePrimes(200001)[%d] == %d, want %d", i, sp[i], 2*q+1)
+		}
+	}
+}
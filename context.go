@@ -0,0 +1,58 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+import "context"
+
+// SieveContext is like Sieve, but it gives up as soon as ctx is done, in
+// which case it returns a nil list and ctx.Err(); this bounds the time
+// spent on a large n, for example by a server with a deadline per request.
+// The primes are found with a segmented sieve of Eratosthenes and ctx is
+// checked after each segment of segmentSize odd numbers, so cancellation
+// takes effect within a fraction of a millisecond.
+// If n is less than 2, it returns an empty list (unless ctx is already done).
+func SieveContext(ctx context.Context, n int) ([]int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if n < 2 {
+		return []int{}, nil
+	}
+	// Unlike Sieve, do not allocate room for all the primes up front, since
+	// the sieve may well be canceled long before it needs it
+	ps := []int{2}
+	var err error
+	sieveSegments(3, int64(n), func(base int64, a []bool) bool {
+		for i, composite := range a {
+			if !composite {
+				ps = append(ps, int(base)+2*i)
+			}
+		}
+		err = ctx.Err()
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ps, nil
+}
@@ -0,0 +1,77 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestSieveContext(t *testing.T) {
+	for _, n := range []int{-1, 0, 1, 2, 3, 100, 10000, 1000000} {
+		ps, err := primes.SieveContext(context.Background(), n)
+		if want := primes.Sieve(n); err != nil || !reflect.DeepEqual(ps, want) {
+			t.Errorf("SieveContext(%d) returned %d primes and %v, want %d primes", n, len(ps), err, len(want))
+		}
+	}
+
+	// A context that is already done stops the sieve before it starts
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if ps, err := primes.SieveContext(ctx, 100); ps != nil || err != context.Canceled {
+		t.Errorf("SieveContext(canceled,100) == (%v,%v), want (nil,%v)", ps, err, context.Canceled)
+	}
+
+	// Cancel a large sieve midway
+	ctx, cancel = context.WithCancel(context.Background())
+	type result struct {
+		ps  []int
+		err error
+	}
+	done := make(chan result)
+	go func() {
+		ps, err := primes.SieveContext(ctx, 1<<31-1)
+		done <- result{ps, err}
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	select {
+	case r := <-done:
+		if r.ps != nil || r.err != context.Canceled {
+			t.Errorf("SieveContext(canceled,2^31-1) returned %d primes and %v, want %v", len(r.ps), r.err, context.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("SieveContext(canceled,2^31-1) did not return within a second of being canceled")
+	}
+
+	// Deadlines work the same way
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if ps, err := primes.SieveContext(ctx, 1<<31-1); ps != nil || err != context.DeadlineExceeded {
+		t.Errorf("SieveContext(timeout,2^31-1) returned %d primes and %v, want %v", len(ps), err, context.DeadlineExceeded)
+	}
+}
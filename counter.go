@@ -0,0 +1,73 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+import (
+	"math"
+	"sort"
+)
+
+// Counter counts the primes less than or equal to any n exactly, like
+// PiExact, but remembers the primes it finds along the way so that
+// repeated queries do not have to sieve the same numbers over and over.
+// Every time it is asked about an n beyond the range it has already sieved,
+// it extends that range with a segmented sieve to at least twice its size,
+// so a sequence of queries at increasing n takes amortized O(n log log n)
+// time overall.
+// The price for the speed is memory: a Counter keeps all the primes up to
+// the largest n it was queried about, that is about n/log(n) ints.
+// The zero value for Counter is ready to use.
+// A Counter is meant for incremental use from a single goroutine; it is not
+// safe for concurrent use.
+type Counter struct {
+	limit int   // all the primes <= limit are in ps
+	ps    []int // all the primes <= limit in increasing order
+}
+
+// Count returns the number of primes less than or equal to n.
+func (c *Counter) Count(n int) int {
+	if n > c.limit {
+		c.grow(n)
+	}
+	return sort.Search(len(c.ps), func(i int) bool { return c.ps[i] > n })
+}
+
+// grow extends the range of primes known to c to cover at least [0,n].
+func (c *Counter) grow(n int) {
+	hi := n
+	if c.limit <= math.MaxInt/2 && 2*c.limit > hi {
+		hi = 2 * c.limit
+	}
+	if c.limit < 2 && hi >= 2 {
+		c.ps = append(c.ps, 2)
+	}
+	sieveSegments(int64(c.limit)+1, int64(hi), func(base int64, a []bool) bool {
+		for i, composite := range a {
+			if !composite {
+				c.ps = append(c.ps, int(base)+2*i)
+			}
+		}
+		return true
+	})
+	c.limit = hi
+}
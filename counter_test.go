@@ -0,0 +1,74 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestCounter(t *testing.T) {
+	var c primes.Counter
+	cases := []struct {
+		n    int
+		want int
+	}{
+		{-1, 0},
+		{0, 0},
+		{1, 0},
+		{2, 1},
+		{3, 2},
+		{10, 4},
+		{100, 25},
+		{10, 4},
+		{1000, 168},
+		{10000, 1229},
+		{104729, 10000},
+		{100000, 9592},
+		{1000000, 78498},
+		{2, 1},
+		{10000000, 664579},
+	}
+	for _, c1 := range cases {
+		if pi := c.Count(c1.n); pi != c1.want {
+			t.Errorf("Counter.Count(%d) == %d, want %d", c1.n, pi, c1.want)
+		}
+	}
+
+	// Check against PiExact in both increasing and random order
+	var c2 primes.Counter
+	for n := 0; n < 200000; n += 1009 {
+		if pi, want := c2.Count(n), primes.PiExact(n); pi != want {
+			t.Errorf("Counter.Count(%d) == %d, want %d", n, pi, want)
+		}
+	}
+	var c3 primes.Counter
+	for i := 0; i < 100; i++ {
+		n := rand.Intn(2000000)
+		if pi, want := c3.Count(n), primes.PiExact(n); pi != want {
+			t.Errorf("Counter.Count(%d) == %d, want %d", n, pi, want)
+		}
+	}
+}
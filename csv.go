@@ -0,0 +1,56 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// WriteFactorizationsCSV writes a table of the prime factorizations of the
+// integers in [lo,hi) to w in CSV format, one row per integer with two
+// fields: the integer and its factorization formatted as by
+// FactorizationString, e.g. "360,2^3 * 3^2 * 5".
+// The factorizations are computed with a sieve over the whole range, which
+// is much faster than factoring each integer separately.
+// It returns the first error encountered while writing to w, if any.
+func WriteFactorizationsCSV(w io.Writer, lo, hi int) error {
+	cw := csv.NewWriter(w)
+	// Integers less than 2 have no prime factors
+	for ; lo < hi && lo < 2; lo++ {
+		if err := cw.Write([]string{strconv.Itoa(lo), FactorizationString(lo)}); err != nil {
+			return err
+		}
+	}
+	var err error
+	factorizeRange(lo, hi, func(n int, fs []primePower) bool {
+		err = cw.Write([]string{strconv.Itoa(n), formatFactorization(fs)})
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
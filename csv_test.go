@@ -0,0 +1,119 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestWriteFactorizationsCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := primes.WriteFactorizationsCSV(&buf, 0, 7); err != nil {
+		t.Fatalf("WriteFactorizationsCSV(_,0,7) returned %v", err)
+	}
+	want := "0,0\n1,1\n2,2\n3,3\n4,2^2\n5,5\n6,2 * 3\n"
+	if s := buf.String(); s != want {
+		t.Errorf("WriteFactorizationsCSV(_,0,7) wrote %q, want %q", s, want)
+	}
+
+	ranges := [][2]int{{-5, 2}, {10, 10}, {10, 5}, {2, 100000}, {999990000, 1000010000}}
+	for _, r := range ranges {
+		buf.Reset()
+		lo, hi := r[0], r[1]
+		if err := primes.WriteFactorizationsCSV(&buf, lo, hi); err != nil {
+			t.Fatalf("WriteFactorizationsCSV(_,%d,%d) returned %v", lo, hi, err)
+		}
+		rows, err := csv.NewReader(&buf).ReadAll()
+		if err != nil {
+			t.Fatalf("WriteFactorizationsCSV(_,%d,%d) wrote invalid CSV: %v", lo, hi, err)
+		}
+		want := hi - lo
+		if want < 0 {
+			want = 0
+		}
+		if len(rows) != want {
+			t.Errorf("WriteFactorizationsCSV(_,%d,%d) wrote %d rows, want %d", lo, hi, len(rows), want)
+		}
+		for i, row := range rows {
+			n, err := strconv.Atoi(row[0])
+			if err != nil || n != lo+i {
+				t.Errorf("WriteFactorizationsCSV(_,%d,%d): row %d is %v", lo, hi, i, row)
+				continue
+			}
+			if n < 2 {
+				continue
+			}
+			// The factors must be prime and multiply back to n
+			prod := 1
+			for _, f := range strings.Split(row[1], " * ") {
+				pk := strings.Split(f, "^")
+				p, _ := strconv.Atoi(pk[0])
+				k := 1
+				if len(pk) > 1 {
+					k, _ = strconv.Atoi(pk[1])
+				}
+				if !primes.IsPrime(p) {
+					t.Errorf("WriteFactorizationsCSV(_,%d,%d): row %v has a factor that is not prime", lo, hi, row)
+				}
+				for ; k > 0; k-- {
+					prod *= p
+				}
+			}
+			if prod != n {
+				t.Errorf("WriteFactorizationsCSV(_,%d,%d): row %v multiplies to %d", lo, hi, row, prod)
+			}
+			if s := primes.FactorizationString(n); s != row[1] {
+				t.Errorf("WriteFactorizationsCSV(_,%d,%d): row %v, want %q", lo, hi, row, s)
+			}
+		}
+	}
+}
+
+// failingWriter fails every write after the first few bytes
+type failingWriter struct {
+	n int
+}
+
+var errFailingWriter = errors.New("write failed")
+
+func (w *failingWriter) Write(b []byte) (int, error) {
+	if w.n < len(b) {
+		return 0, errFailingWriter
+	}
+	w.n -= len(b)
+	return len(b), nil
+}
+
+func TestWriteFactorizationsCSVError(t *testing.T) {
+	w := &failingWriter{n: 100}
+	if err := primes.WriteFactorizationsCSV(w, 0, 100000); err != errFailingWriter {
+		t.Errorf("WriteFactorizationsCSV(failingWriter,0,100000) returned %v, want %v", err, errFailingWriter)
+	}
+}
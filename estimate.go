@@ -0,0 +1,182 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+import "math"
+
+// eulerGamma is the Euler-Mascheroni constant
+const eulerGamma = 0.57721566490153286060651209008240243
+
+// li2 is the value of the logarithmic integral li(2)
+const li2 = 1.04516378011749278484458888919461313
+
+// logIntegral returns the (offset) logarithmic integral Li(x), that is the
+// integral of 1/log(t) for t in [2,x], for any x >= 2.
+// It evaluates li(x) with Ramanujan's rapidly converging series and
+// subtracts li(2) from it.
+// See https://en.wikipedia.org/wiki/Logarithmic_integral_function for details.
+func logIntegral(x float64) float64 {
+	lnx := math.Log(x)
+	// term == (-1)^(k-1) * lnx^k / (k! * 2^(k-1))
+	// inner == sum of 1/(2j+1) for j in [0,(k-1)/2]
+	sum, term, inner := 0.0, 2.0, 0.0
+	for k := 1; k < 1000; k++ {
+		term *= -lnx / (2 * float64(k))
+		if k%2 == 1 {
+			inner += 1 / float64(k)
+		}
+		delta := -term * inner
+		sum += delta
+		if math.Abs(delta) < 1e-17*math.Abs(sum) {
+			break
+		}
+	}
+	return eulerGamma + math.Log(lnx) + math.Sqrt(x)*sum - li2
+}
+
+// PiErrorEstimate returns three estimates of the number of primes less than
+// or equal to n side by side, so that their errors can be compared: the
+// estimate Pi(n) uses beyond the range of cached primes (based on Riemann's
+// function R(n)), the offset logarithmic integral Li(n), and the simple
+// estimate n/log(n).
+// Unlike Pi, it returns the estimate even for n within the cache.
+// The last two bracket the exact count: n/log(n) < pi(n) for all n >= 11,
+// and pi(n) < Li(n) for all n >= 8 up to at least 10^19, although Skewes
+// showed that Li(n)-pi(n) changes sign eventually; the first estimate is
+// far closer to pi(n) than either bound (see tests).
+// If n is less than 2, it returns zeros.
+// See https://en.wikipedia.org/wiki/Prime-counting_function for details.
+func PiErrorEstimate(n int) (estimate int, li float64, pnt float64) {
+	if n < 2 {
+		return 0, 0, 0
+	}
+	x := float64(n)
+	return int(riemannR(x)), logIntegral(x), x / math.Log(x)
+}
+
+// PiWithBounds is like Pi, but it returns proven lower and upper bounds on
+// the number of primes less than or equal to n together with the estimate,
+// so that the worst-case error of the estimate is known.
+// If n is within the range of cached primes, all three values are the exact
+// count; beyond that, the bounds are Dusart's inequalities
+//
+//	n/log(n) * (1 + 1/log(n)) <= pi(n) <= n/log(n) * (1 + 1.2762/log(n))
+//
+// the first of which holds for n >= 599 and the second for n > 1, which
+// tighten to n/log(n) * (1 + 1/log(n) + 2/log(n)^2) <= pi(n) for
+// n >= 88789 and pi(n) <= n/log(n) * (1 + 1/log(n) + 2.51/log(n)^2) for
+// n >= 355991.
+// The bounds bracket the estimate of Pi, but they are much looser than its
+// actual error: they are about 0.2% apart at n == 10^7 and 0.03% apart at
+// n == 10^18.
+// See https://en.wikipedia.org/wiki/Prime-counting_function#Inequalities
+// for details.
+func PiWithBounds(n int) (estimate, lower, upper int) {
+	estimate, ok := Pi(n)
+	if ok {
+		return estimate, estimate, estimate
+	}
+	x := float64(n)
+	l := math.Log(x)
+	lo := x / l * (1 + 1/l)
+	if n >= 88789 {
+		lo = x / l * (1 + 1/l + 2/(l*l))
+	}
+	hi := x / l * (1 + 1.2762/l)
+	if n >= 355991 {
+		hi = x / l * (1 + 1/l + 2.51/(l*l))
+	}
+	lower, upper = int(math.Ceil(lo)), int(hi)
+	// Keep the estimate within the bounds
+	if estimate < lower {
+		estimate = lower
+	} else if estimate > upper {
+		estimate = upper
+	}
+	return estimate, lower, upper
+}
+
+// riemannR returns Riemann's prime-counting function R(x), that is the sum
+// of mu(k)/k * li(x^(1/k)) for k >= 1, for any x >= 2.
+// The sum is truncated as soon as x^(1/k) drops below 2, since the
+// remaining terms are too small to affect the integer part of R(x) over the
+// range of float64 values Pi deals with.
+// See https://en.wikipedia.org/wiki/Prime-counting_function#Exact_form for
+// details.
+func riemannR(x float64) float64 {
+	r := 0.0
+	for k := 1; ; k++ {
+		y := math.Pow(x, 1/float64(k))
+		if y < 2 {
+			break
+		}
+		mu := mobiusSmall(k)
+		if mu != 0 {
+			r += float64(mu) / float64(k) * (logIntegral(y) + li2)
+		}
+	}
+	return r
+}
+
+// mobiusSmall returns the Mobius function mu(k) for small positive k by
+// trial division; unlike Mobius, it does not need the cache of primes, so
+// it is safe to use while the cache itself is being built by Sieve.
+func mobiusSmall(k int) int {
+	mu := 1
+	for d := 2; d*d <= k; d++ {
+		if k%d == 0 {
+			k /= d
+			if k%d == 0 {
+				return 0
+			}
+			mu = -mu
+		}
+	}
+	if k > 1 {
+		mu = -mu
+	}
+	return mu
+}
+
+// NthPrimeEstimate returns an estimate of the n-th prime p_n, counting from
+// p_1 == 2.
+// For n within the range of cached primes, the estimate is exact; beyond
+// that, it is Cipolla's asymptotic expansion
+// n*(log(n) + log(log(n)) - 1 + (log(log(n))-2)/log(n)),
+// whose relative error is below 1% just past the cache and below 0.1% for
+// n >= 10^5 (see tests), far better than the simpler estimate n*log(n).
+// If n is less than 1, it returns 0.
+// See https://en.wikipedia.org/wiki/Prime_number_theorem#Approximations_for_the_nth_prime_number
+// for details.
+func NthPrimeEstimate(n int) int {
+	if n < 1 {
+		return 0
+	}
+	if primes, _ := cachedPrimes(); n <= len(primes) {
+		return primes[n-1]
+	}
+	x := float64(n)
+	lnx := math.Log(x)
+	lnlnx := math.Log(lnx)
+	return int(x * (lnx + lnlnx - 1 + (lnlnx-2)/lnx))
+}
@@ -0,0 +1,143 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestNthPrimeEstimate(t *testing.T) {
+	// Exact values within the cache
+	ps := primes.Sieve(10000)
+	for i, p := range ps {
+		if e := primes.NthPrimeEstimate(i + 1); e != p {
+			t.Errorf("NthPrimeEstimate(%d) == %d, want %d", i+1, e, p)
+		}
+	}
+	for _, n := range []int{-1, 0} {
+		if e := primes.NthPrimeEstimate(n); e != 0 {
+			t.Errorf("NthPrimeEstimate(%d) == %d, want 0", n, e)
+		}
+	}
+
+	// See https://oeis.org/A006988
+	cases := []struct {
+		n    int64
+		want int64
+		eps  float64
+	}{
+		{1230, 10007, 0.01},
+		{2000, 17389, 0.01},
+		{10000, 104729, 0.005},
+		{100000, 1299709, 0.001},
+		{1000000, 15485863, 0.001},
+		{10000000, 179424673, 0.001},
+		{100000000, 2038074743, 0.001},
+		{1000000000, 22801763489, 0.001},
+		{1000000000000, 29996224275833, 0.001},
+	}
+	for _, c := range cases {
+		n := int(c.n)
+		if int64(n) != c.n || int64(int(c.want)) != c.want {
+			// n or p_n does not fit in an int on this platform
+			continue
+		}
+		e := primes.NthPrimeEstimate(n)
+		if eps := math.Abs(float64(int64(e)-c.want) / float64(c.want)); eps >= c.eps {
+			t.Errorf("NthPrimeEstimate(%d) == %d, want %d; eps=%f", c.n, e, c.want, eps)
+		}
+	}
+}
+
+func TestPiErrorEstimate(t *testing.T) {
+	for _, n := range []int{-1, 0, 1} {
+		if e, li, pnt := primes.PiErrorEstimate(n); e != 0 || li != 0 || pnt != 0 {
+			t.Errorf("PiErrorEstimate(%d) == (%d,%f,%f), want zeros", n, e, li, pnt)
+		}
+	}
+
+	// n/log(n) < pi(n) < Li(n) for n >= 11 and the estimate is the one
+	// used by Pi
+	ps := primes.Sieve(1000000)
+	for n := 11; n <= 1000000; n += 1 + n/1000 {
+		pi := sort.SearchInts(ps, n+1)
+		e, li, pnt := primes.PiErrorEstimate(n)
+		if !(pnt < float64(pi) && float64(pi) < li) {
+			t.Errorf("PiErrorEstimate(%d) == (%d,%f,%f), but pi(%d) == %d", n, e, li, pnt, n, pi)
+		}
+		if estimate, ok := primes.Pi(n); !ok && e != estimate {
+			t.Errorf("PiErrorEstimate(%d) == (%d,%f,%f), want estimate %d", n, e, li, pnt, estimate)
+		}
+		// The estimate is closer to pi(n) than the bounds beyond small n
+		err := math.Abs(float64(e - pi))
+		if n > 1000 && (err > li-float64(pi) || err > float64(pi)-pnt) {
+			t.Errorf("PiErrorEstimate(%d) == (%d,%f,%f), but pi(%d) == %d", n, e, li, pnt, n, pi)
+		}
+	}
+}
+
+func TestPiWithBounds(t *testing.T) {
+	// The exact counts are always within the bounds
+	for _, c := range piCounts {
+		n := int(c.n)
+		if int64(n) != c.n {
+			// n does not fit in an int on this platform
+			continue
+		}
+		e, lower, upper := primes.PiWithBounds(n)
+		if int64(lower) > c.want || int64(upper) < c.want || e < lower || e > upper {
+			t.Errorf("PiWithBounds(%d) == (%d,%d,%d), want bounds around %d", c.n, e, lower, upper, c.want)
+		}
+		if estimate, _ := primes.Pi(n); e != estimate {
+			t.Errorf("PiWithBounds(%d) == (%d,%d,%d), want estimate %d", c.n, e, lower, upper, estimate)
+		}
+	}
+
+	// Check every range of the bounds against exact counts
+	ps := primes.Sieve(2000000)
+	for n := -1; n <= 2000000; n += 1 + n/100 {
+		pi := sort.SearchInts(ps, n+1)
+		e, lower, upper := primes.PiWithBounds(n)
+		if lower > pi || upper < pi || e < lower || e > upper {
+			t.Errorf("PiWithBounds(%d) == (%d,%d,%d), want bounds around %d", n, e, lower, upper, pi)
+		}
+		if n <= 10000 && (e != pi || lower != pi || upper != pi) {
+			t.Errorf("PiWithBounds(%d) == (%d,%d,%d), want exact count %d", n, e, lower, upper, pi)
+		}
+	}
+
+	// Check every n around the points where the bounds switch to the
+	// sharper inequalities
+	for _, m := range []int{88789, 355991} {
+		for n := m - 300; n <= m+300; n++ {
+			pi := sort.SearchInts(ps, n+1)
+			if _, lower, upper := primes.PiWithBounds(n); lower > pi || upper < pi {
+				t.Errorf("PiWithBounds(%d) has bounds (%d,%d), want bounds around %d", n, lower, upper, pi)
+			}
+		}
+	}
+}
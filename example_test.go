@@ -79,6 +79,22 @@ func ExampleCoprime() {
 	// 5 and 6 are coprime
 }
 
+func ExampleGCD() {
+	// Compute the greatest common divisor and least common multiple
+	// of a few pairs
+	pairs := [][2]int{{12, 18}, {-4, 6}, {17, 31}, {0, 9}}
+	for _, p := range pairs {
+		fmt.Printf("GCD(%d,%d) = %d, LCM(%d,%d) = %d\n",
+			p[0], p[1], primes.GCD(p[0], p[1]), p[0], p[1], primes.LCM(p[0], p[1]))
+	}
+
+	// Output:
+	// GCD(12,18) = 6, LCM(12,18) = 36
+	// GCD(-4,6) = 2, LCM(-4,6) = 12
+	// GCD(17,31) = 1, LCM(17,31) = 527
+	// GCD(0,9) = 9, LCM(0,9) = 0
+}
+
 func ExamplePi() {
 	// Check how many prime numbers are less than or equal to n
 	ns := []int{6, 11, 23, 12345}
@@ -95,5 +111,56 @@ func ExamplePi() {
 	// There are 3 primes in [0,6]
 	// There are 5 primes in [0,11]
 	// There are 9 primes in [0,23]
-	// There are approximately 1465 primes in [0,12345]
+	// There are approximately 1477 primes in [0,12345]
+}
+
+func ExampleDivisors() {
+	// List all the positive divisors of a few numbers
+	for _, n := range []int{12, 13, 36} {
+		fmt.Printf("%d: %v\n", n, primes.Divisors(n))
+	}
+
+	// Output:
+	// 12: [1 2 3 4 6 12]
+	// 13: [1 13]
+	// 36: [1 2 3 4 6 9 12 18 36]
+}
+
+func ExampleFactorTree() {
+	// Print the factorization tree of 360
+	fmt.Print(primes.FactorTree(360))
+
+	// Output:
+	// 360
+	//   2
+	//   180
+	//     2
+	//     90
+	//       2
+	//       45
+	//         3
+	//         15
+	//           3
+	//           5
+}
+
+func ExampleTwinPrimes() {
+	// List the twin primes up to 50
+	fmt.Println(primes.TwinPrimes(50))
+
+	// Output: [[3 5] [5 7] [11 13] [17 19] [29 31] [41 43]]
+}
+
+func ExampleGoldbach() {
+	// Write a few even numbers as the sum of two primes
+	for _, n := range []int{4, 28, 100, 1000} {
+		a, b, _ := primes.Goldbach(n)
+		fmt.Printf("%d = %d + %d\n", n, a, b)
+	}
+
+	// Output:
+	// 4 = 2 + 2
+	// 28 = 5 + 23
+	// 100 = 3 + 97
+	// 1000 = 3 + 997
 }
@@ -0,0 +1,573 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// factorize calls f(p,k) for each prime factor p of n in increasing order,
+// where k is the multiplicity of p in the factorization of n.
+// It stops as soon as f returns false and does nothing if n is less than 2.
+// It uses trial division by the cached primes first and by the numbers
+// of the form 6*k+|-1 larger than the largest prime in the cache after that,
+// stopping as soon as the divisor squared exceeds what is left of n.
+func factorize(n int, f func(p, k int) bool) {
+	if n < 2 {
+		return
+	}
+	// divideOut divides n by p as many times as possible and reports p
+	// to f if it was a factor; it returns false if f asked to stop.
+	divideOut := func(p int) bool {
+		k := 0
+		for n%p == 0 {
+			n /= p
+			k++
+		}
+		return k == 0 || f(p, k)
+	}
+	primes, _ := cachedPrimes()
+	for _, p := range primes {
+		if p > n/p {
+			break
+		}
+		if !divideOut(p) {
+			return
+		}
+	}
+	pMax := primes[len(primes)-1]
+	for d := (pMax/6+1)*6 - 1; d <= n/d; d += 6 {
+		if !divideOut(d) || !divideOut(d+2) {
+			return
+		}
+	}
+	// Whatever is left has no divisor other than itself
+	if n > 1 {
+		f(n, 1)
+	}
+}
+
+// primePower represents the prime power p^k as a factor of a number
+type primePower struct {
+	p, k int
+}
+
+// factorizeRange calls f(n,fs) for each n in [lo,hi) in increasing order,
+// where lo >= 2 and fs lists the prime factors of n with their
+// multiplicities in increasing order of the primes.
+// It stops as soon as f returns false.
+// Rather than factoring each number separately, it runs a sieve over the
+// range, one segment at a time, dividing every multiple of each prime up to
+// sqrt(hi) by that prime; whatever is left after that is a prime factor.
+// The list fs is only valid until f returns.
+func factorizeRange(lo, hi int, f func(n int, fs []primePower) bool) {
+	if lo < 2 {
+		lo = 2
+	}
+	if lo >= hi {
+		return
+	}
+	ps := Sieve(isqrt(hi - 1))
+	rem := make([]int, segmentSize)
+	fss := make([][]primePower, segmentSize)
+	for base := lo; ; base += segmentSize {
+		length := segmentSize
+		if hi-base < length {
+			length = hi - base
+		}
+		for i := 0; i < length; i++ {
+			rem[i] = base + i
+			fss[i] = fss[i][:0]
+		}
+		for _, p := range ps {
+			// Start from the first multiple of p no smaller than base
+			for i := (p - base%p) % p; i < length; i += p {
+				k := 0
+				for rem[i]%p == 0 {
+					rem[i] /= p
+					k++
+				}
+				fss[i] = append(fss[i], primePower{p, k})
+			}
+		}
+		for i := 0; i < length; i++ {
+			if rem[i] > 1 {
+				fss[i] = append(fss[i], primePower{rem[i], 1})
+			}
+			if !f(base+i, fss[i]) {
+				return
+			}
+		}
+		if hi-base <= segmentSize {
+			return
+		}
+	}
+}
+
+// formatFactorization formats the prime factors in fs as a product of
+// prime powers, e.g. "2^3 * 3^2 * 5", omitting any exponent equal to 1.
+func formatFactorization(fs []primePower) string {
+	var b strings.Builder
+	for i, f := range fs {
+		if i > 0 {
+			b.WriteString(" * ")
+		}
+		b.WriteString(strconv.Itoa(f.p))
+		if f.k > 1 {
+			b.WriteByte('^')
+			b.WriteString(strconv.Itoa(f.k))
+		}
+	}
+	return b.String()
+}
+
+// factorizeAbs is like factorize, but it factors the absolute value of n,
+// so it works for negative n too (math.MinInt included).
+func factorizeAbs(n int, f func(p, k int) bool) {
+	if n >= 0 {
+		factorize(n, f)
+		return
+	}
+	// Divide out the factors 2 first so that -n cannot overflow
+	k := 0
+	for n%2 == 0 {
+		n /= 2
+		k++
+	}
+	if k > 0 && !f(2, k) {
+		return
+	}
+	factorize(-n, f)
+}
+
+// SmallestPrimeFactor returns the smallest prime factor of n, which is n
+// itself if n is prime; it returns 0 if n is less than 2.
+// It uses trial division by the cached primes first and by the numbers of
+// the form 6*k+|-1 after that, stopping at the first divisor found, so it
+// is much cheaper than a full factorization unless n has no small factors.
+func SmallestPrimeFactor(n int) int {
+	spf := 0
+	factorize(n, func(p, k int) bool {
+		spf = p
+		return false
+	})
+	return spf
+}
+
+// LargestPrimeFactor returns the largest prime factor of n, which is n
+// itself if n is prime; it returns 0 if n is less than 2.
+// It divides out the prime factors of n in increasing order, so the last
+// cofactor left is the largest prime factor; this takes as long as a full
+// factorization of n.
+func LargestPrimeFactor(n int) int {
+	lpf := 0
+	factorize(n, func(p, k int) bool {
+		lpf = p
+		return true
+	})
+	return lpf
+}
+
+// DistinctPrimeFactors returns the distinct prime factors of n, each listed
+// once and in increasing order; for example, DistinctPrimeFactors(360)
+// returns [2 3 5].
+// Its length is the number of distinct prime factors of n and their product
+// is the radical of n (see Radical).
+// If n is less than 2, it returns an empty list.
+func DistinctPrimeFactors(n int) []int {
+	ps := []int{}
+	factorize(n, func(p, k int) bool {
+		ps = append(ps, p)
+		return true
+	})
+	return ps
+}
+
+// FactorizeMap returns the prime factorization of n as a map from each
+// prime factor of n to its multiplicity; for example, FactorizeMap(360)
+// returns map[2:3 3:2 5:1].
+// It uses trial division by the cached primes first and by the numbers of
+// the form 6*k+|-1 after that, so it can be slow when n has two or more
+// large prime factors.
+// If n is less than 2, it returns an empty map.
+func FactorizeMap(n int) map[int]int {
+	fs := make(map[int]int)
+	factorize(n, func(p, k int) bool {
+		fs[p] = k
+		return true
+	})
+	return fs
+}
+
+// FactorizeWith factors n by trial division with the given list of primes
+// only, so that callers can reuse a list computed once (e.g. by Sieve) and
+// control how much work goes into each factorization.
+// It returns the prime factors found, in increasing order and repeated
+// according to their multiplicity, together with the cofactor of n that
+// is left after dividing them out.
+// The list ps must hold the consecutive primes from 2 up to some bound, in
+// increasing order: if the square of the next prime (in the list or after
+// it) exceeds what is left of n, the cofactor must be prime and is included
+// among the factors, so passing Sieve(sqrt(n)) always fully factors n and
+// leaves a cofactor of 1.
+// Otherwise, the cofactor has no prime factors in ps, but it may be
+// composite.
+// If n is less than 2, it returns an empty list and n as the cofactor.
+func FactorizeWith(n int, ps []int) (factors []int, cofactor int) {
+	factors = []int{}
+	if n < 2 {
+		return factors, n
+	}
+	for _, p := range ps {
+		if p > n/p {
+			// n has no divisor other than itself
+			return append(factors, n), 1
+		}
+		for n%p == 0 {
+			n /= p
+			factors = append(factors, p)
+		}
+		if n == 1 {
+			return factors, 1
+		}
+	}
+	// What is left has no prime factors in ps; if it is smaller than the
+	// square of the next prime, it must be prime itself (if the next prime
+	// does not even fit in an int, no other factor can)
+	next := 2
+	if len(ps) > 0 {
+		next = NextPrimeInclusive(ps[len(ps)-1] + 1)
+	}
+	if next == 0 || next > n/next {
+		return append(factors, n), 1
+	}
+	return factors, n
+}
+
+// SPFSieve returns a table of the smallest prime factors of the integers
+// in [0,n]: element i of the table is SmallestPrimeFactor(i), or 0 for
+// i < 2.
+// The table is built with a sieve of Eratosthenes that records, for each
+// composite number, the first prime that marks it off; it takes O(n) memory
+// and O(n log log n) time, after which FactorizeWithSPF can factor any
+// number up to n in O(log n) time.
+// If n is negative, it returns an empty table.
+func SPFSieve(n int) []int {
+	if n < 0 {
+		return []int{}
+	}
+	spf := make([]int, n+1)
+	for i := 2; i <= n; i++ {
+		if spf[i] != 0 {
+			continue
+		}
+		// i is prime; its multiples below i*i have a smaller prime factor
+		spf[i] = i
+		if i > n/i {
+			continue
+		}
+		for j := i * i; j <= n; j += i {
+			if spf[j] == 0 {
+				spf[j] = i
+			}
+		}
+	}
+	return spf
+}
+
+// FactorizeWithSPF returns the prime factorization of m as a map from each
+// prime factor of m to its multiplicity, like FactorizeMap, but it looks up
+// the factors in the table spf returned by SPFSieve, dividing m by its
+// smallest prime factor until nothing is left; this takes O(log m) time,
+// which makes factoring many numbers below the same bound much faster.
+// If m is beyond the range of the table, it falls back on FactorizeMap.
+// If m is less than 2, it returns an empty map.
+func FactorizeWithSPF(spf []int, m int) map[int]int {
+	if m >= len(spf) {
+		return FactorizeMap(m)
+	}
+	fs := make(map[int]int)
+	for m >= 2 {
+		p := spf[m]
+		fs[p]++
+		m /= p
+	}
+	return fs
+}
+
+// IsPrimePower returns the prime p and the exponent k >= 1 such that
+// n == p^k, if there are any; for example, IsPrimePower(27) returns
+// (3,3,true) and IsPrimePower(12) returns (0,0,false).
+// It factors n and gives up as soon as a second prime factor shows up.
+// If n is less than 2, ok is false.
+func IsPrimePower(n int) (p, k int, ok bool) {
+	factorize(n, func(q, j int) bool {
+		if ok {
+			// Found a second prime factor
+			ok = false
+			return false
+		}
+		p, k, ok = q, j, true
+		return true
+	})
+	if !ok {
+		return 0, 0, false
+	}
+	return p, k, true
+}
+
+// DominantPrimeFactor returns the prime factor of n with the largest
+// multiplicity, together with that multiplicity; if several prime factors
+// share the largest multiplicity, it returns the smallest of them.
+// For example, 360 == 2^3 * 3^2 * 5, so DominantPrimeFactor(360) == (2,3).
+// If n is less than 2, it returns (0,0).
+func DominantPrimeFactor(n int) (prime, exp int) {
+	for p, k := range FactorizeMap(n) {
+		if k > exp || k == exp && p < prime {
+			prime, exp = p, k
+		}
+	}
+	return
+}
+
+// Divisors returns the positive divisors of n in increasing order;
+// for example, Divisors(12) returns [1 2 3 4 6 12].
+// The divisors are generated by multiplying together all the combinations
+// of the prime powers in the factorization of n.
+// If n is less than 1, it returns an empty list.
+func Divisors(n int) []int {
+	if n < 1 {
+		return []int{}
+	}
+	ds := []int{1}
+	factorize(n, func(p, k int) bool {
+		m := len(ds)
+		q := 1
+		for i := 0; i < k; i++ {
+			q *= p
+			for _, d := range ds[:m] {
+				ds = append(ds, d*q)
+			}
+		}
+		return true
+	})
+	sort.Ints(ds)
+	return ds
+}
+
+// DivisorCount returns the number of positive divisors of n, usually
+// denoted by tau(n) or d(n); for example, DivisorCount(12) == 6.
+// It is computed directly from the factorization of n as the product of (k+1)
+// over the prime factors of n with multiplicity k.
+// If n is less than 1, it returns 0.
+// See https://en.wikipedia.org/wiki/Divisor_function for details.
+func DivisorCount(n int) int {
+	if n < 1 {
+		return 0
+	}
+	tau := 1
+	factorize(n, func(p, k int) bool {
+		tau *= k + 1
+		return true
+	})
+	return tau
+}
+
+// DivisorSum returns the sum of the positive divisors of n, usually
+// denoted by sigma(n); for example, DivisorSum(12) == 28.
+// It is computed directly from the factorization of n as the product of
+// (p^(k+1)-1)/(p-1) == 1+p+...+p^k over the prime factors of n with
+// multiplicity k.
+// If n is less than 1, it returns 0.
+// Note that sigma(n) can be several times larger than n (it grows like
+// n*log(log(n)) in the worst case), so the result may overflow for n
+// close to the largest int; this is not detected.
+// See https://en.wikipedia.org/wiki/Divisor_function for details.
+func DivisorSum(n int) int {
+	if n < 1 {
+		return 0
+	}
+	sigma := 1
+	factorize(n, func(p, k int) bool {
+		s, q := 1, 1
+		for i := 0; i < k; i++ {
+			q *= p
+			s += q
+		}
+		sigma *= s
+		return true
+	})
+	return sigma
+}
+
+// PrimeSignature returns the multiset of exponents in the prime factorization
+// of n, sorted in non-increasing order; for example, the signature of
+// 360 = 2^3 * 3^2 * 5 is [3 2 1].
+// If n is less than 2, it returns an empty list.
+// See https://en.wikipedia.org/wiki/Prime_signature for details.
+func PrimeSignature(n int) []int {
+	sig := []int{}
+	factorize(n, func(p, k int) bool {
+		sig = append(sig, k)
+		return true
+	})
+	sort.Sort(sort.Reverse(sort.IntSlice(sig)))
+	return sig
+}
+
+// MinimalWithSignature returns the smallest positive integer whose prime
+// signature is sig (the order of the exponents in sig does not matter).
+// It is computed by assigning the largest exponents to the smallest primes,
+// so that, for example, the signature [2 1] maps to 2^2 * 3 = 12.
+// Non-positive exponents are ignored.
+// If the result does not fit in an int, it returns 0.
+func MinimalWithSignature(sig []int) int {
+	es := make([]int, len(sig))
+	copy(es, sig)
+	sort.Sort(sort.Reverse(sort.IntSlice(es)))
+	primes, _ := cachedPrimes()
+	m := 1
+	for i, e := range es {
+		if e <= 0 {
+			break
+		}
+		if i >= len(primes) {
+			// Already way past the largest int
+			return 0
+		}
+		p := primes[i]
+		for ; e > 0; e-- {
+			if m > math.MaxInt/p {
+				return 0
+			}
+			m *= p
+		}
+	}
+	return m
+}
+
+// MultiplicativePartitions returns the number of ways n can be written as
+// an unordered product of integers greater than 1, counting n itself as one
+// such product; for example, 12 has four of them: 12, 6*2, 4*3, and 3*2*2.
+// By convention, MultiplicativePartitions(1) == 1.
+// If n is less than 1, it returns 0.
+// The count is computed recursively by choosing the largest factor of the
+// product among the divisors of n and then counting the ways its cofactor
+// can be written as a product of factors no larger than that.
+// See https://en.wikipedia.org/wiki/Multiplicative_partition for details.
+func MultiplicativePartitions(n int) int {
+	if n < 1 {
+		return 0
+	}
+	// Candidate factors are the divisors of n greater than 1
+	ds := Divisors(n)[1:]
+	type key struct{ m, max int }
+	memo := make(map[key]int)
+	var count func(m, max int) int
+	count = func(m, max int) int {
+		if m == 1 {
+			return 1
+		}
+		k := key{m, max}
+		if c, ok := memo[k]; ok {
+			return c
+		}
+		c := 0
+		for _, d := range ds {
+			if d > max || d > m {
+				break
+			}
+			if m%d == 0 {
+				c += count(m/d, d)
+			}
+		}
+		memo[k] = c
+		return c
+	}
+	return count(n, n)
+}
+
+// DistinctPrimesInSlice returns the primes that divide at least one of the
+// elements of ns, in increasing order and without duplicates.
+// Each element is factored in turn; negative elements are factored by their
+// absolute value, and 0, 1, and -1 do not contribute any prime.
+func DistinctPrimesInSlice(ns []int) []int {
+	seen := make(map[int]bool)
+	ps := []int{}
+	for _, n := range ns {
+		factorizeAbs(n, func(p, k int) bool {
+			if !seen[p] {
+				seen[p] = true
+				ps = append(ps, p)
+			}
+			return true
+		})
+	}
+	sort.Ints(ps)
+	return ps
+}
+
+// FactorizationString returns the prime factorization of n formatted as a
+// product of prime powers in increasing order of the primes, with exponents
+// equal to 1 omitted; for example, FactorizationString(360) returns
+// "2^3 * 3^2 * 5".
+// If n is less than 2, it has no prime factors and FactorizationString(n)
+// simply returns n formatted in decimal.
+func FactorizationString(n int) string {
+	if n < 2 {
+		return strconv.Itoa(n)
+	}
+	fs := []primePower{}
+	factorize(n, func(p, k int) bool {
+		fs = append(fs, primePower{p, k})
+		return true
+	})
+	return formatFactorization(fs)
+}
+
+// FactorString returns the prime factorization of n as an equation, for
+// display purposes; for example, FactorString(360) returns
+// "360 = 2^3 * 3^2 * 5" and FactorString(7) returns "7 = 7".
+// The right-hand side is formatted like FactorizationString(n), but a
+// negative n is written as -1 times the factorization of its absolute
+// value, so that FactorString(-12) returns "-12 = -1 * 2^2 * 3".
+// Since 0, 1, and -1 have no prime factors, they are simply equal to
+// themselves: FactorString(1) returns "1 = 1".
+func FactorString(n int) string {
+	fs := []primePower{}
+	factorizeAbs(n, func(p, k int) bool {
+		fs = append(fs, primePower{p, k})
+		return true
+	})
+	rhs := formatFactorization(fs)
+	switch {
+	case len(fs) == 0:
+		rhs = strconv.Itoa(n)
+	case n < 0:
+		rhs = "-1 * " + rhs
+	}
+	return strconv.Itoa(n) + " = " + rhs
+}
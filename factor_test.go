@@ -0,0 +1,638 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestFactorizeMap(t *testing.T) {
+	cases := []struct {
+		n    int
+		want map[int]int
+	}{
+		{-12, map[int]int{}},
+		{0, map[int]int{}},
+		{1, map[int]int{}},
+		{2, map[int]int{2: 1}},
+		{12, map[int]int{2: 2, 3: 1}},
+		{360, map[int]int{2: 3, 3: 2, 5: 1}},
+		{9973, map[int]int{9973: 1}},
+		{10007 * 10009, map[int]int{10007: 1, 10009: 1}},
+		{1 << 30, map[int]int{2: 30}},
+	}
+	for _, c := range cases {
+		fs := primes.FactorizeMap(c.n)
+		if !reflect.DeepEqual(fs, c.want) {
+			t.Errorf("FactorizeMap(%d) == %v, want %v", c.n, fs, c.want)
+		}
+	}
+
+	// The factors must be prime and multiply back to n
+	for n := 2; n <= 100000; n++ {
+		prod := 1
+		for p, k := range primes.FactorizeMap(n) {
+			if !primes.IsPrime(p) || k < 1 {
+				t.Errorf("FactorizeMap(%d) contains %d:%d", n, p, k)
+			}
+			for ; k > 0; k-- {
+				prod *= p
+			}
+		}
+		if prod != n {
+			t.Errorf("FactorizeMap(%d) multiplies to %d", n, prod)
+		}
+	}
+}
+
+func TestFactorizeWith(t *testing.T) {
+	ps := primes.Sieve(100)
+	cases := []struct {
+		n        int
+		ps       []int
+		factors  []int
+		cofactor int
+	}{
+		{-12, ps, []int{}, -12},
+		{0, ps, []int{}, 0},
+		{1, ps, []int{}, 1},
+		{2, ps, []int{2}, 1},
+		{3, nil, []int{3}, 1},
+		{360, ps, []int{2, 2, 2, 3, 3, 5}, 1},
+		{360, ps[:2], []int{2, 2, 2, 3, 3, 5}, 1},
+		{360, ps[:1], []int{2, 2, 2}, 45},
+		{360, nil, []int{}, 360},
+		{9973, ps, []int{9973}, 1},
+		{9973 * 4, ps[:1], []int{2, 2}, 9973},
+		{10007 * 10009, ps, []int{}, 10007 * 10009},
+		{97 * 10007 * 10009, ps, []int{97}, 10007 * 10009},
+	}
+	for _, c := range cases {
+		factors, cofactor := primes.FactorizeWith(c.n, c.ps)
+		if !reflect.DeepEqual(factors, c.factors) || cofactor != c.cofactor {
+			t.Errorf("FactorizeWith(%d,%v) == (%v,%d), want (%v,%d)",
+				c.n, c.ps, factors, cofactor, c.factors, c.cofactor)
+		}
+	}
+
+	for n := 2; n <= 20000; n++ {
+		// The primes up to sqrt(n) are enough to factor n fully
+		sqrtn := int(math.Sqrt(float64(n)))
+		factors, cofactor := primes.FactorizeWith(n, primes.Sieve(sqrtn))
+		prod := 1
+		for _, p := range factors {
+			prod *= p
+		}
+		if cofactor != 1 || prod != n {
+			t.Errorf("FactorizeWith(%d,Sieve(%d)) == (%v,%d)", n, sqrtn, factors, cofactor)
+		}
+
+		// A short list leaves a cofactor with no small prime factors
+		factors, cofactor = primes.FactorizeWith(n, ps[:3])
+		prod = cofactor
+		for _, p := range factors {
+			prod *= p
+		}
+		if prod != n || !primes.Coprime(cofactor, 2*3*5) {
+			t.Errorf("FactorizeWith(%d,%v) == (%v,%d)", n, ps[:3], factors, cofactor)
+		}
+	}
+}
+
+func TestSmallestPrimeFactor(t *testing.T) {
+	cases := []struct {
+		n    int
+		want int
+	}{
+		{-12, 0},
+		{0, 0},
+		{1, 0},
+		{2, 2},
+		{9, 3},
+		{35, 5},
+		{9973, 9973},
+		{9973 * 9973, 9973},
+		{10007 * 10009, 10007},
+		{1000003, 1000003},
+		{1 << 30, 2},
+	}
+	for _, c := range cases {
+		if p := primes.SmallestPrimeFactor(c.n); p != c.want {
+			t.Errorf("SmallestPrimeFactor(%d) == %d, want %d", c.n, p, c.want)
+		}
+	}
+
+	ps := primes.Sieve(20000)
+	for i, p := range ps {
+		// Primes are their own smallest factor and so are prime squares
+		if q := primes.SmallestPrimeFactor(p); q != p {
+			t.Errorf("SmallestPrimeFactor(%d) == %d, want %d", p, q, p)
+		}
+		if q := primes.SmallestPrimeFactor(p * p); q != p {
+			t.Errorf("SmallestPrimeFactor(%d) == %d, want %d", p*p, q, p)
+		}
+		// Even numbers have 2 as their smallest factor
+		if q := primes.SmallestPrimeFactor(2 * p); q != 2 {
+			t.Errorf("SmallestPrimeFactor(%d) == %d, want 2", 2*p, q)
+		}
+		if i > 0 {
+			if n, q := p*ps[i-1], primes.SmallestPrimeFactor(p*ps[i-1]); q != ps[i-1] {
+				t.Errorf("SmallestPrimeFactor(%d) == %d, want %d", n, q, ps[i-1])
+			}
+		}
+	}
+}
+
+func TestLargestPrimeFactor(t *testing.T) {
+	cases := []struct {
+		n    int64
+		want int64
+	}{
+		{-12, 0},
+		{0, 0},
+		{1, 0},
+		{2, 2},
+		{12, 3},
+		{13195, 29},
+		{9973, 9973},
+		{9973 * 9973, 9973},
+		{1 << 30, 2},
+		{600851475143, 6857},
+		// A large semiprime
+		{999983 * 1000003, 1000003},
+	}
+	for _, c := range cases {
+		n := int(c.n)
+		if int64(n) != c.n {
+			// n does not fit in an int on this platform
+			continue
+		}
+		if p := primes.LargestPrimeFactor(n); int64(p) != c.want {
+			t.Errorf("LargestPrimeFactor(%d) == %d, want %d", c.n, p, c.want)
+		}
+	}
+
+	// Check against the factorization
+	for n := 2; n <= 20000; n++ {
+		want := 0
+		for p := range primes.FactorizeMap(n) {
+			if p > want {
+				want = p
+			}
+		}
+		if p := primes.LargestPrimeFactor(n); p != want {
+			t.Errorf("LargestPrimeFactor(%d) == %d, want %d", n, p, want)
+		}
+	}
+}
+
+func TestDistinctPrimeFactors(t *testing.T) {
+	cases := []struct {
+		n    int
+		want []int
+	}{
+		{-12, []int{}},
+		{0, []int{}},
+		{1, []int{}},
+		{2, []int{2}},
+		{12, []int{2, 3}},
+		{360, []int{2, 3, 5}},
+		{9973, []int{9973}},
+		{9973 * 9973, []int{9973}},
+		{1 << 30, []int{2}},
+		{2 * 3 * 5 * 7 * 11 * 13 * 17 * 19 * 23, []int{2, 3, 5, 7, 11, 13, 17, 19, 23}},
+	}
+	for _, c := range cases {
+		if ps := primes.DistinctPrimeFactors(c.n); !reflect.DeepEqual(ps, c.want) {
+			t.Errorf("DistinctPrimeFactors(%d) == %v, want %v", c.n, ps, c.want)
+		}
+	}
+
+	// Check against the factorization and the radical
+	for n := 2; n <= 20000; n++ {
+		ps := primes.DistinctPrimeFactors(n)
+		if fs := primes.FactorizeMap(n); len(ps) != len(fs) {
+			t.Errorf("DistinctPrimeFactors(%d) == %v, want the primes in %v", n, ps, fs)
+		}
+		rad := 1
+		for i, p := range ps {
+			if n%p != 0 || !primes.IsPrime(p) || (i > 0 && p <= ps[i-1]) {
+				t.Errorf("DistinctPrimeFactors(%d) == %v", n, ps)
+				break
+			}
+			rad *= p
+		}
+		if want := primes.Radical(n); rad != want {
+			t.Errorf("DistinctPrimeFactors(%d) == %v, whose product is not %d", n, ps, want)
+		}
+	}
+}
+
+func TestSPFSieve(t *testing.T) {
+	cases := []struct {
+		n    int
+		want []int
+	}{
+		{-1, []int{}},
+		{0, []int{0}},
+		{1, []int{0, 0}},
+		{12, []int{0, 0, 2, 3, 2, 5, 2, 7, 2, 3, 2, 11, 2}},
+	}
+	for _, c := range cases {
+		if spf := primes.SPFSieve(c.n); !reflect.DeepEqual(spf, c.want) {
+			t.Errorf("SPFSieve(%d) == %v, want %v", c.n, spf, c.want)
+		}
+	}
+
+	spf := primes.SPFSieve(100000)
+	for i, p := range spf {
+		if want := primes.SmallestPrimeFactor(i); p != want {
+			t.Errorf("SPFSieve(100000)[%d] == %d, want %d", i, p, want)
+		}
+	}
+}
+
+func TestFactorizeWithSPF(t *testing.T) {
+	// Factor every number up to 10^5 with the table and without it
+	const n = 100000
+	spf := primes.SPFSieve(n)
+	for m := -1; m <= n; m++ {
+		fs := primes.FactorizeWithSPF(spf, m)
+		if want := primes.FactorizeMap(m); !reflect.DeepEqual(fs, want) {
+			t.Errorf("FactorizeWithSPF(spf,%d) == %v, want %v", m, fs, want)
+		}
+	}
+
+	// Numbers beyond the table are still factored
+	for _, m := range []int{n + 1, 10007 * 10009} {
+		fs := primes.FactorizeWithSPF(spf, m)
+		if want := primes.FactorizeMap(m); !reflect.DeepEqual(fs, want) {
+			t.Errorf("FactorizeWithSPF(spf,%d) == %v, want %v", m, fs, want)
+		}
+	}
+}
+
+func TestIsPrimePower(t *testing.T) {
+	cases := []struct {
+		n    int
+		p, k int
+		ok   bool
+	}{
+		{-8, 0, 0, false},
+		{0, 0, 0, false},
+		{1, 0, 0, false},
+		{2, 2, 1, true},
+		{12, 0, 0, false},
+		{27, 3, 3, true},
+		{1 << 30, 2, 30, true},
+		{9973, 9973, 1, true},
+		{9973 * 9973, 9973, 2, true},
+		{10007 * 10007, 10007, 2, true},
+		{46337 * 46337, 46337, 2, true},
+		{9973 * 9973 * 2, 0, 0, false},
+	}
+	for _, c := range cases {
+		if p, k, ok := primes.IsPrimePower(c.n); p != c.p || k != c.k || ok != c.ok {
+			t.Errorf("IsPrimePower(%d) == (%d,%d,%v), want (%d,%d,%v)", c.n, p, k, ok, c.p, c.k, c.ok)
+		}
+	}
+
+	// Check against the factorization
+	for n := 2; n <= 20000; n++ {
+		fs := primes.FactorizeMap(n)
+		p, k, ok := primes.IsPrimePower(n)
+		if ok != (len(fs) == 1) || ok && fs[p] != k {
+			t.Errorf("IsPrimePower(%d) == (%d,%d,%v), but FactorizeMap(%d) == %v", n, p, k, ok, n, fs)
+		}
+	}
+}
+
+func TestDominantPrimeFactor(t *testing.T) {
+	cases := []struct {
+		n          int
+		prime, exp int
+	}{
+		{-8, 0, 0},
+		{0, 0, 0},
+		{1, 0, 0},
+		{2, 2, 1},
+		{6, 2, 1},
+		{12, 2, 2},
+		{18, 3, 2},
+		{36, 2, 2},
+		{360, 2, 3},
+		{2 * 27 * 25, 3, 3},
+		{9 * 25 * 49, 3, 2},
+		{25 * 49, 5, 2},
+		{9973, 9973, 1},
+		{1 << 30, 2, 30},
+		{3 * 3 * 3 * 3 * 3 * 3 * 3 * 3 * 3 * 3, 3, 10},
+	}
+	for _, c := range cases {
+		p, k := primes.DominantPrimeFactor(c.n)
+		if p != c.prime || k != c.exp {
+			t.Errorf("DominantPrimeFactor(%d) == (%d,%d), want (%d,%d)", c.n, p, k, c.prime, c.exp)
+		}
+	}
+}
+
+// baselineDivisors returns the positive divisors of n in increasing order.
+// It uses trial division against all d in [1,sqrt(n)].
+// Used for testing only.
+func baselineDivisors(n int) []int {
+	lo, hi := []int{}, []int{}
+	for d := 1; d*d <= n; d++ {
+		if n%d == 0 {
+			lo = append(lo, d)
+			if d*d != n {
+				hi = append([]int{n / d}, hi...)
+			}
+		}
+	}
+	return append(lo, hi...)
+}
+
+func TestDivisors(t *testing.T) {
+	cases := []struct {
+		n    int
+		want []int
+	}{
+		{-12, []int{}},
+		{0, []int{}},
+		{1, []int{1}},
+		{2, []int{1, 2}},
+		{12, []int{1, 2, 3, 4, 6, 12}},
+		{36, []int{1, 2, 3, 4, 6, 9, 12, 18, 36}},
+		{9973, []int{1, 9973}},
+		{2 * 10007, []int{1, 2, 10007, 2 * 10007}},
+	}
+	for _, c := range cases {
+		ds := primes.Divisors(c.n)
+		if !reflect.DeepEqual(ds, c.want) {
+			t.Errorf("Divisors(%d) == %v, want %v", c.n, ds, c.want)
+		}
+	}
+
+	for n := 1; n <= 100000; n++ {
+		ds := primes.Divisors(n)
+		want := baselineDivisors(n)
+		if !reflect.DeepEqual(ds, want) {
+			t.Errorf("Divisors(%d) == %v, want %v", n, ds, want)
+		}
+	}
+}
+
+func TestDivisorCountAndSum(t *testing.T) {
+	cases := []struct {
+		n     int
+		tau   int
+		sigma int
+	}{
+		{-12, 0, 0},
+		{0, 0, 0},
+		{1, 1, 1},
+		{2, 2, 3},
+		{12, 6, 28},
+		{28, 6, 56},
+		{360, 24, 1170},
+		{9973, 2, 9974},
+		{1 << 30, 31, 1<<31 - 1},
+	}
+	for _, c := range cases {
+		if tau := primes.DivisorCount(c.n); tau != c.tau {
+			t.Errorf("DivisorCount(%d) == %d, want %d", c.n, tau, c.tau)
+		}
+		if sigma := primes.DivisorSum(c.n); sigma != c.sigma {
+			t.Errorf("DivisorSum(%d) == %d, want %d", c.n, sigma, c.sigma)
+		}
+	}
+
+	for n := 1; n <= 20000; n++ {
+		ds := baselineDivisors(n)
+		sum := 0
+		for _, d := range ds {
+			sum += d
+		}
+		if tau := primes.DivisorCount(n); tau != len(ds) {
+			t.Errorf("DivisorCount(%d) == %d, want %d", n, tau, len(ds))
+		}
+		if sigma := primes.DivisorSum(n); sigma != sum {
+			t.Errorf("DivisorSum(%d) == %d, want %d", n, sigma, sum)
+		}
+	}
+}
+
+func TestPrimeSignature(t *testing.T) {
+	cases := []struct {
+		n    int
+		want []int
+	}{
+		{-12, []int{}},
+		{0, []int{}},
+		{1, []int{}},
+		{2, []int{1}},
+		{12, []int{2, 1}},
+		{18, []int{2, 1}},
+		{360, []int{3, 2, 1}},
+		{9973, []int{1}},
+		{1000003, []int{1}},
+		{1 << 30, []int{30}},
+		{2 * 9973 * 9973, []int{2, 1}},
+		{2 * 97 * 97 * 10007, []int{2, 1, 1}},
+	}
+	for _, c := range cases {
+		sig := primes.PrimeSignature(c.n)
+		if !reflect.DeepEqual(sig, c.want) {
+			t.Errorf("PrimeSignature(%d) == %v, want %v", c.n, sig, c.want)
+		}
+	}
+}
+
+func TestMinimalWithSignature(t *testing.T) {
+	cases := []struct {
+		sig  []int
+		want int
+	}{
+		{nil, 1},
+		{[]int{1}, 2},
+		{[]int{2}, 4},
+		{[]int{1, 1}, 6},
+		{[]int{2, 1}, 12},
+		{[]int{1, 2}, 12},
+		{[]int{3, 1}, 24},
+		{[]int{1, 1, 1}, 30},
+		{[]int{2, 2}, 36},
+		{[]int{1, 2, 3}, 360},
+		{[]int{30}, 1 << 30},
+		{[]int{64}, 0},
+		{[]int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}, 0},
+	}
+	for _, c := range cases {
+		m := primes.MinimalWithSignature(c.sig)
+		if m != c.want {
+			t.Errorf("MinimalWithSignature(%v) == %d, want %d", c.sig, m, c.want)
+		}
+		if m == 0 {
+			continue
+		}
+		// The signature of the result must match the input signature
+		want := append([]int{}, c.sig...)
+		sort.Sort(sort.Reverse(sort.IntSlice(want)))
+		if sig := primes.PrimeSignature(m); !reflect.DeepEqual(sig, want) {
+			t.Errorf("PrimeSignature(MinimalWithSignature(%v)) == %v, want %v", c.sig, sig, want)
+		}
+	}
+
+	// No smaller number can have the same signature
+	for _, sig := range [][]int{{1}, {2}, {2, 1}, {3, 1}, {2, 2}, {2, 1, 1}} {
+		m := primes.MinimalWithSignature(sig)
+		for n := 1; n < m; n++ {
+			if reflect.DeepEqual(primes.PrimeSignature(n), sig) {
+				t.Errorf("PrimeSignature(%d) == %v, smaller than MinimalWithSignature(%v) == %d", n, sig, sig, m)
+			}
+		}
+	}
+}
+
+func TestMultiplicativePartitions(t *testing.T) {
+	// The first few terms of https://oeis.org/A001055
+	want := []int{
+		1, 1, 1, 2, 1, 2, 1, 3, 2, 2, 1, 4, 1, 2, 2, 5, 1, 4,
+		1, 4, 2, 2, 1, 7, 2, 2, 3, 4, 1, 5, 1, 7, 2, 2, 2, 9,
+	}
+	for i, w := range want {
+		n := i + 1
+		if c := primes.MultiplicativePartitions(n); c != w {
+			t.Errorf("MultiplicativePartitions(%d) == %d, want %d", n, c, w)
+		}
+	}
+
+	cases := []struct {
+		n    int
+		want int
+	}{
+		{-1, 0},
+		{0, 0},
+		{720, 98},
+		{9973, 1},
+		// The multiplicative partitions of p^k match the partitions of k
+		{1 << 10, 42},
+		{3 * 3 * 3 * 3 * 3 * 3 * 3 * 3 * 3 * 3 * 3 * 3, 77},
+	}
+	for _, c := range cases {
+		if n := primes.MultiplicativePartitions(c.n); n != c.want {
+			t.Errorf("MultiplicativePartitions(%d) == %d, want %d", c.n, n, c.want)
+		}
+	}
+}
+
+func TestDistinctPrimesInSlice(t *testing.T) {
+	cases := []struct {
+		ns   []int
+		want []int
+	}{
+		{nil, []int{}},
+		{[]int{0, 1, -1}, []int{}},
+		{[]int{12}, []int{2, 3}},
+		{[]int{12, 18, 35}, []int{2, 3, 5, 7}},
+		{[]int{35, 18, 12}, []int{2, 3, 5, 7}},
+		{[]int{-12, 9973, 9973 * 3}, []int{2, 3, 9973}},
+		{[]int{1 << 20, 1 << 10}, []int{2}},
+	}
+	for _, c := range cases {
+		ps := primes.DistinctPrimesInSlice(c.ns)
+		if !reflect.DeepEqual(ps, c.want) {
+			t.Errorf("DistinctPrimesInSlice(%v) == %v, want %v", c.ns, ps, c.want)
+		}
+	}
+
+	// The union of the prime factors of [2,n] are the primes up to n
+	ns := []int{}
+	for n := 1000; n >= 2; n-- {
+		ns = append(ns, n)
+	}
+	ps := primes.DistinctPrimesInSlice(ns)
+	if want := primes.Sieve(1000); !reflect.DeepEqual(ps, want) {
+		t.Errorf("DistinctPrimesInSlice([1000..2]) == %v, want %v", ps, want)
+	}
+}
+
+func TestFactorizationString(t *testing.T) {
+	cases := []struct {
+		n    int
+		want string
+	}{
+		{-12, "-12"},
+		{0, "0"},
+		{1, "1"},
+		{2, "2"},
+		{12, "2^2 * 3"},
+		{360, "2^3 * 3^2 * 5"},
+		{9973, "9973"},
+		{1 << 30, "2^30"},
+	}
+	for _, c := range cases {
+		if s := primes.FactorizationString(c.n); s != c.want {
+			t.Errorf("FactorizationString(%d) == %q, want %q", c.n, s, c.want)
+		}
+	}
+}
+
+func TestFactorString(t *testing.T) {
+	cases := []struct {
+		n    int
+		want string
+	}{
+		{math.MinInt32, "-2147483648 = -1 * 2^31"},
+		{-360, "-360 = -1 * 2^3 * 3^2 * 5"},
+		{-7, "-7 = -1 * 7"},
+		{-1, "-1 = -1"},
+		{0, "0 = 0"},
+		{1, "1 = 1"},
+		{2, "2 = 2"},
+		{12, "12 = 2^2 * 3"},
+		{360, "360 = 2^3 * 3^2 * 5"},
+		{729, "729 = 3^6"},
+		{9973, "9973 = 9973"},
+		{1 << 30, "1073741824 = 2^30"},
+	}
+	for _, c := range cases {
+		if s := primes.FactorString(c.n); s != c.want {
+			t.Errorf("FactorString(%d) == %q, want %q", c.n, s, c.want)
+		}
+	}
+
+	// Check against FactorizationString
+	for n := 2; n < 10000; n++ {
+		want := strconv.Itoa(n) + " = " + primes.FactorizationString(n)
+		if s := primes.FactorString(n); s != want {
+			t.Errorf("FactorString(%d) == %q, want %q", n, s, want)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+import (
+	"math"
+	"math/big"
+)
+
+// EulerPhi returns the number of integers k in [1,n] that are coprime to n.
+// It factors n and applies Euler's product formula n*(1-1/p) over the
+// distinct prime factors p of n, so that EulerPhi(p) == p-1 for any prime p.
+// If n is less than 1, it returns 0.
+// See https://en.wikipedia.org/wiki/Euler%27s_totient_function for details.
+func EulerPhi(n int) int {
+	if n < 1 {
+		return 0
+	}
+	phi := n
+	factorize(n, func(p, k int) bool {
+		phi = phi / p * (p - 1)
+		return true
+	})
+	return phi
+}
+
+// DedekindPsi returns the Dedekind psi function of n, that is the product
+// n*(1+1/p) over the distinct prime factors p of n, so that
+// DedekindPsi(p) == p+1 for any prime p and DedekindPsi(1) == 1.
+// It is the index in the modular group of its congruence subgroup of
+// level n and, like EulerPhi, it is multiplicative.
+// If n is less than 1, it returns 0.
+// See https://en.wikipedia.org/wiki/Dedekind_psi_function for details.
+func DedekindPsi(n int) int {
+	if n < 1 {
+		return 0
+	}
+	psi := n
+	for p := range FactorizeMap(n) {
+		psi = psi / p * (p + 1)
+	}
+	return psi
+}
+
+// Radical returns the radical (or squarefree kernel) of n, that is the
+// product of the distinct prime factors of n; for example,
+// Radical(360) == 2*3*5 == 30 and Radical(1) == 1.
+// It multiplies together the keys of FactorizeMap(n). Since the radical of
+// n divides n, the product cannot overflow.
+// If n is less than 1, it returns 0.
+// See https://en.wikipedia.org/wiki/Radical_of_an_integer for details.
+func Radical(n int) int {
+	if n < 1 {
+		return 0
+	}
+	rad := 1
+	for p := range FactorizeMap(n) {
+		rad *= p
+	}
+	return rad
+}
+
+// TotientSummatory returns the sum of EulerPhi(k) for k in [1,n], which is
+// also the number of coprime pairs (a,b) with 1 <= a <= b <= n.
+// The sum grows like 3n^2/pi^2, so it is returned as a big.Int.
+// The totients are computed all at once with a sieve that, for each prime
+// p, multiplies the running totient of every multiple of p by (1-1/p); the
+// sieve takes O(n log log n) time and O(n) memory.
+// If n is less than 1, it returns 0.
+// See https://en.wikipedia.org/wiki/Arithmetic_function#Summatory_functions
+// and https://oeis.org/A002088 for details.
+func TotientSummatory(n int) *big.Int {
+	total := new(big.Int)
+	if n < 1 {
+		return total
+	}
+	// phi[k] == k as long as the sieve has not found a prime factor of k
+	phi := make([]int, n+1)
+	for k := range phi {
+		phi[k] = k
+	}
+	var sum uint64
+	for k := 1; k <= n; k++ {
+		if phi[k] == k && k > 1 {
+			for m := k; m <= n; m += k {
+				phi[m] -= phi[m] / k
+			}
+		}
+		// Move the partial sum to total before it can overflow
+		if sum > math.MaxUint64-uint64(phi[k]) {
+			total.Add(total, new(big.Int).SetUint64(sum))
+			sum = 0
+		}
+		sum += uint64(phi[k])
+	}
+	return total.Add(total, new(big.Int).SetUint64(sum))
+}
+
+// Mobius returns the Mobius function mu(n): it returns 0 if n is divisible
+// by the square of a prime and (-1)^k otherwise, where k is the number of
+// distinct prime factors of n; in particular, Mobius(1) == 1.
+// It factors n and stops as soon as it finds a repeated prime factor.
+// If n is less than 1, it returns 0.
+// See https://en.wikipedia.org/wiki/M%C3%B6bius_function for details.
+func Mobius(n int) int {
+	if n < 1 {
+		return 0
+	}
+	mu := 1
+	factorize(n, func(p, k int) bool {
+		if k > 1 {
+			mu = 0
+			return false
+		}
+		mu = -mu
+		return true
+	})
+	return mu
+}
+
+// Omega returns omega(n), the number of distinct prime factors of n; for
+// example, Omega(12) == 2 since 12 == 2^2*3.
+// It counts the keys of FactorizeMap(n).
+// If n is less than 2, it returns 0.
+// See https://en.wikipedia.org/wiki/Prime_omega_function for details.
+func Omega(n int) int {
+	return len(FactorizeMap(n))
+}
+
+// BigOmega returns Omega(n), the number of prime factors of n counted with
+// their multiplicity; for example, BigOmega(12) == 3 since 12 == 2^2*3.
+// It adds up the values of FactorizeMap(n); the Liouville function lambda(n)
+// is (-1)^BigOmega(n).
+// If n is less than 2, it returns 0.
+// See https://en.wikipedia.org/wiki/Prime_omega_function for details.
+func BigOmega(n int) int {
+	k := 0
+	for _, e := range FactorizeMap(n) {
+		k += e
+	}
+	return k
+}
+
+// IsSquareFree returns true if n is squarefree, that is if n is not
+// divisible by the square of any prime; for example, IsSquareFree(30) is
+// true while IsSquareFree(12) is false, and IsSquareFree(1) is true.
+// It factors n and returns as soon as it finds a repeated prime factor, so
+// it is as fast as the factorization of n at worst.
+// If n is less than 1, it returns false.
+// See https://en.wikipedia.org/wiki/Square-free_integer for details.
+func IsSquareFree(n int) bool {
+	if n < 1 {
+		return false
+	}
+	squareFree := true
+	factorize(n, func(p, k int) bool {
+		squareFree = k == 1
+		return squareFree
+	})
+	return squareFree
+}
+
+// IsSmooth returns true if n is bound-smooth, that is if none of the prime
+// factors of n is greater than bound; for example, IsSmooth(360,5) is true
+// while IsSmooth(42,5) is false, and IsSmooth(1,bound) is true for any
+// bound.
+// It factors n and returns as soon as it finds a prime factor greater than
+// bound.
+// If n is less than 1, it returns false.
+// See https://en.wikipedia.org/wiki/Smooth_number for details.
+func IsSmooth(n, bound int) bool {
+	if n < 1 {
+		return false
+	}
+	smooth := true
+	factorize(n, func(p, k int) bool {
+		smooth = p <= bound
+		return smooth
+	})
+	return smooth
+}
+
+// TotientChainLength returns the number of times EulerPhi must be applied
+// to n to reach 1; for example, TotientChainLength(3) == 2 since
+// EulerPhi(3) == 2 and EulerPhi(2) == 1.
+// The chain always reaches 1 since EulerPhi(n) < n for any n > 1, and it
+// takes O(log(n)) steps since EulerPhi(n) is even for n > 2 and
+// EulerPhi(m) <= m/2 for any even m; grouping numbers by the length of
+// their chain gives Shapiro's classes of the totient function.
+// If n is less than 2, it returns 0.
+// See https://oeis.org/A003434 for details.
+func TotientChainLength(n int) int {
+	length := 0
+	for ; n > 1; n = EulerPhi(n) {
+		length++
+	}
+	return length
+}
+
+// SumOverPrimes returns the sum of f(p) over the primes p less than or equal
+// to n, adding the terms in increasing order of p.
+// Many functions of the primes are sums of this kind: for example, f(p) ==
+// log(p) gives Chebyshev's function theta(n), f(p) == 1/p gives the sum of
+// the reciprocals of the primes, which grows like log(log(n)), and
+// f(p) == p^-s gives the partial sums of the prime zeta function P(s).
+// The primes are generated by SieveFunc in a single pass, without storing
+// them.
+// If n is less than 2, it returns 0.
+// See https://en.wikipedia.org/wiki/Chebyshev_function and
+// https://en.wikipedia.org/wiki/Prime_zeta_function for details.
+func SumOverPrimes(n int, f func(p int) float64) float64 {
+	sum := 0.0
+	SieveFunc(n, func(p int) {
+		sum += f(p)
+	})
+	return sum
+}
+
+// SumPrimes returns the sum of the primes less than or equal to n; for
+// example, SumPrimes(10) == 2+3+5+7 == 17.
+// The primes are added up by SieveFunc as they are found, without storing
+// them.
+// The sum grows like n^2/(2*log(n)): the sum of the primes below 2*10^9 is
+// about 9.5*10^16, which fits comfortably in a 64-bit int, and the sum only
+// overflows for n beyond roughly 2*10^10, where the sieve runs out of
+// memory first; with a 32-bit int, it overflows once n reaches 225,287.
+// If n is less than 2, it returns 0.
+// See https://oeis.org/A034387 and https://projecteuler.net/problem=10 for
+// details.
+func SumPrimes(n int) int {
+	sum := 0
+	SieveFunc(n, func(p int) {
+		sum += p
+	})
+	return sum
+}
+
+// SumFirstNPrimes returns the sum of the first n primes; for example,
+// SumFirstNPrimes(10) == 2+3+5+...+29 == 129.
+// The sum grows like n^2*log(n)/2, so it stays within an int64 until
+// roughly the 9*10^8-th prime; if it overflows, ok is false.
+// The primes are generated by a PrimeIterator, so the sum takes only a
+// little memory however large n is.
+// If n is less than 1, it returns (0,true).
+// See https://oeis.org/A007504 for details.
+func SumFirstNPrimes(n int) (sum int64, ok bool) {
+	var it PrimeIterator
+	for i := 0; i < n; i++ {
+		p := int64(it.Next())
+		if p == 0 || sum > math.MaxInt64-p {
+			return 0, false
+		}
+		sum += p
+	}
+	return sum, true
+}
+
+// ZetaEulerProduct returns the partial Euler product for the Riemann zeta
+// function zeta(s), that is the product of 1/(1-p^-s) over the primes p
+// less than or equal to n.
+// For s > 1, the product converges to zeta(s) as n grows; for example,
+// ZetaEulerProduct(2,n) approaches pi^2/6 with an error of about
+// 1/(n*log(n)).
+// The primes are generated by SieveFunc in a single pass, without storing
+// them.
+// If n is less than 2, it returns 1 (the empty product).
+// See https://en.wikipedia.org/wiki/Proof_of_the_Euler_product_formula_for_the_Riemann_zeta_function
+// for details.
+func ZetaEulerProduct(s float64, n int) float64 {
+	prod := 1.0
+	SieveFunc(n, func(p int) {
+		prod /= 1 - math.Pow(float64(p), -s)
+	})
+	return prod
+}
+
+// AverageOmega returns the average number of distinct prime factors of the
+// integers in [2,n].
+// The Hardy-Ramanujan theorem implies that this average grows like
+// log(log(n)); more precisely, it tends to log(log(n)) + 0.2615 as n grows.
+// The counts are computed with a sieve that, for each prime p, adds one to
+// the count of every multiple of p; the sieve takes O(n) memory.
+// If n is less than 2, it returns 0.
+// See https://en.wikipedia.org/wiki/Hardy%E2%80%93Ramanujan_theorem for details.
+func AverageOmega(n int) float64 {
+	if n < 2 {
+		return 0
+	}
+	// omega[k] is the number of distinct prime factors of k found so far;
+	// if it is still 0 when the sieve gets to k, then k is prime
+	omega := make([]uint8, n+1)
+	sum := 0
+	for k := 2; k <= n; k++ {
+		if omega[k] == 0 {
+			for m := k; m <= n; m += k {
+				omega[m]++
+			}
+		}
+		sum += int(omega[k])
+	}
+	return float64(sum) / float64(n-1)
+}
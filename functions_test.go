@@ -0,0 +1,603 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestEulerPhi(t *testing.T) {
+	cases := []struct {
+		n    int
+		want int
+	}{
+		{-1, 0},
+		{0, 0},
+		{1, 1},
+		{2, 1},
+		{12, 4},
+		{36, 12},
+		{9973, 9972},
+		{1000003, 1000002},
+		{1 << 30, 1 << 29},
+	}
+	for _, c := range cases {
+		phi := primes.EulerPhi(c.n)
+		if phi != c.want {
+			t.Errorf("EulerPhi(%d) == %d, want %d", c.n, phi, c.want)
+		}
+	}
+
+	// Check against a count of the coprimes in [1,n]
+	for n := 1; n <= 2000; n++ {
+		want := 0
+		for k := 1; k <= n; k++ {
+			if primes.Coprime(k, n) {
+				want++
+			}
+		}
+		if phi := primes.EulerPhi(n); phi != want {
+			t.Errorf("EulerPhi(%d) == %d, want %d", n, phi, want)
+		}
+	}
+}
+
+func TestDedekindPsi(t *testing.T) {
+	cases := []struct {
+		n    int
+		want int
+	}{
+		{-1, 0},
+		{0, 0},
+		{1, 1},
+		{2, 3},
+		{4, 6},
+		{12, 24},
+		{360, 864},
+		{9973 * 9973, 9973 * 9974},
+	}
+	for _, c := range cases {
+		psi := primes.DedekindPsi(c.n)
+		if psi != c.want {
+			t.Errorf("DedekindPsi(%d) == %d, want %d", c.n, psi, c.want)
+		}
+	}
+
+	// DedekindPsi(p) == p+1 for any prime p
+	for _, p := range primes.Sieve(20000) {
+		if psi := primes.DedekindPsi(p); psi != p+1 {
+			t.Errorf("DedekindPsi(%d) == %d, want %d", p, psi, p+1)
+		}
+	}
+
+	// Check against the sum of d*|Mobius(n/d)| over the divisors d of n;
+	// see https://oeis.org/A001615
+	for n := 1; n <= 2000; n++ {
+		want := 0
+		for _, d := range primes.Divisors(n) {
+			want += d * primes.Mobius(n/d) * primes.Mobius(n/d)
+		}
+		if psi := primes.DedekindPsi(n); psi != want {
+			t.Errorf("DedekindPsi(%d) == %d, want %d", n, psi, want)
+		}
+	}
+}
+
+func TestRadical(t *testing.T) {
+	cases := []struct {
+		n    int
+		want int
+	}{
+		{-1, 0},
+		{0, 0},
+		{1, 1},
+		{2, 2},
+		{12, 6},
+		{360, 30},
+		{1 << 30, 2},
+		{9973 * 9973, 9973},
+	}
+	for _, c := range cases {
+		rad := primes.Radical(c.n)
+		if rad != c.want {
+			t.Errorf("Radical(%d) == %d, want %d", c.n, rad, c.want)
+		}
+	}
+
+	ps := primes.Sieve(1000)
+	for _, p := range ps {
+		// The radical of a prime power is its base
+		for q := p; q <= 1<<30/p; q *= p {
+			if rad := primes.Radical(q); rad != p {
+				t.Errorf("Radical(%d) == %d, want %d", q, rad, p)
+			}
+		}
+	}
+
+	// The radical of a squarefree number is the number itself
+	for n := 1; n <= 5000; n++ {
+		if primes.Mobius(n) != 0 {
+			if rad := primes.Radical(n); rad != n {
+				t.Errorf("Radical(%d) == %d, want %d", n, rad, n)
+			}
+		}
+	}
+}
+
+func TestTotientSummatory(t *testing.T) {
+	cases := []struct {
+		n    int
+		want int64
+	}{
+		{-1, 0},
+		{0, 0},
+		{1, 1},
+		{2, 2},
+		{10, 32},
+		{100, 3044},
+		{1000, 304192},
+		{1000000, 303963552392},
+	}
+	for _, c := range cases {
+		sum := primes.TotientSummatory(c.n)
+		if !sum.IsInt64() || sum.Int64() != c.want {
+			t.Errorf("TotientSummatory(%d) == %v, want %d", c.n, sum, c.want)
+		}
+	}
+
+	// Check against the sum of EulerPhi and the count of coprime pairs
+	want, pairs := int64(0), int64(0)
+	for n := 1; n <= 300; n++ {
+		want += int64(primes.EulerPhi(n))
+		for a := 1; a <= n; a++ {
+			if primes.Coprime(a, n) {
+				pairs++
+			}
+		}
+		sum := primes.TotientSummatory(n)
+		if sum.Int64() != want || sum.Int64() != pairs {
+			t.Errorf("TotientSummatory(%d) == %v, want %d", n, sum, want)
+		}
+	}
+
+	// The sum approaches 3n^2/pi^2
+	for _, n := range []int{1000, 100000, 2000000} {
+		sum, _ := new(big.Float).SetInt(primes.TotientSummatory(n)).Float64()
+		approx := 3 * float64(n) * float64(n) / (math.Pi * math.Pi)
+		if eps := math.Abs(sum-approx) / approx; eps > math.Log(float64(n))/float64(n) {
+			t.Errorf("TotientSummatory(%d) == %.0f, want about %.0f; eps=%g", n, sum, approx, eps)
+		}
+	}
+}
+
+func TestMobius(t *testing.T) {
+	cases := []struct {
+		n    int
+		want int
+	}{
+		{-1, 0},
+		{0, 0},
+		{1, 1},
+		{2, -1},
+		{4, 0},
+		{6, 1},
+		{12, 0},
+		{30, -1},
+		{9973, -1},
+		{9973 * 9973, 0},
+		{2 * 3 * 5 * 7 * 11 * 13 * 17 * 19 * 23, -1},
+	}
+	for _, c := range cases {
+		mu := primes.Mobius(c.n)
+		if mu != c.want {
+			t.Errorf("Mobius(%d) == %d, want %d", c.n, mu, c.want)
+		}
+	}
+
+	// Check the Mertens function M(n), that is the sum of Mobius(k) for k
+	// in [1,n]; see https://oeis.org/A002321
+	mertens := []struct {
+		n    int
+		want int
+	}{
+		{1, 1},
+		{2, 0},
+		{3, -1},
+		{5, -2},
+		{10, -1},
+		{100, 1},
+		{1000, 2},
+		{10000, -23},
+		{100000, -48},
+	}
+	m, k := 0, 0
+	for _, c := range mertens {
+		for k < c.n {
+			k++
+			m += primes.Mobius(k)
+		}
+		if m != c.want {
+			t.Errorf("M(%d) == %d, want %d", c.n, m, c.want)
+		}
+	}
+}
+
+func TestOmegaAndBigOmega(t *testing.T) {
+	cases := []struct {
+		n            int
+		omega, bigOm int
+	}{
+		{-12, 0, 0},
+		{0, 0, 0},
+		{1, 0, 0},
+		{2, 1, 1},
+		{12, 2, 3},
+		{360, 3, 6},
+		{9973, 1, 1},
+		{9973 * 9967, 2, 2},
+		{2 * 3 * 5 * 7 * 11 * 13 * 17 * 19 * 23, 9, 9},
+	}
+	for _, c := range cases {
+		if w := primes.Omega(c.n); w != c.omega {
+			t.Errorf("Omega(%d) == %d, want %d", c.n, w, c.omega)
+		}
+		if w := primes.BigOmega(c.n); w != c.bigOm {
+			t.Errorf("BigOmega(%d) == %d, want %d", c.n, w, c.bigOm)
+		}
+	}
+
+	// Prime powers p^k have a single prime factor repeated k times
+	for _, p := range primes.Sieve(1000) {
+		for q, k := p, 1; q <= 1<<30/p; q, k = q*p, k+1 {
+			if w := primes.Omega(q); w != 1 {
+				t.Errorf("Omega(%d) == %d, want 1", q, w)
+			}
+			if w := primes.BigOmega(q); w != k {
+				t.Errorf("BigOmega(%d) == %d, want %d", q, w, k)
+			}
+		}
+	}
+
+	// Omega(n) <= BigOmega(n), with equality exactly for the squarefree n,
+	// and Mobius(n) == (-1)^Omega(n) for those
+	for n := 1; n <= 20000; n++ {
+		w, bigW := primes.Omega(n), primes.BigOmega(n)
+		if sf := primes.IsSquareFree(n); w > bigW || (w == bigW) != sf {
+			t.Errorf("Omega(%d) == %d, BigOmega(%d) == %d, IsSquareFree(%d) == %v", n, w, n, bigW, n, sf)
+		} else if sf && primes.Mobius(n) != 1-2*(w%2) {
+			t.Errorf("Mobius(%d) == %d, but Omega(%d) == %d", n, primes.Mobius(n), n, w)
+		}
+	}
+}
+
+func TestIsSquareFree(t *testing.T) {
+	cases := []struct {
+		n    int
+		want bool
+	}{
+		{-6, false},
+		{0, false},
+		{1, true},
+		{2, true},
+		{4, false},
+		{12, false},
+		{30, true},
+		{9973 * 9967, true},
+		{9973 * 9973, false},
+		{2 * 3 * 5 * 7 * 11 * 13 * 17 * 19 * 23, true},
+	}
+	for _, c := range cases {
+		sf := primes.IsSquareFree(c.n)
+		if sf != c.want {
+			t.Errorf("IsSquareFree(%d) == %v, want %v", c.n, sf, c.want)
+		}
+	}
+
+	ps := primes.Sieve(1000)
+	for i, p := range ps {
+		// Primes are squarefree, but their higher powers never are
+		if !primes.IsSquareFree(p) {
+			t.Errorf("IsSquareFree(%d) == false, want true", p)
+		}
+		for q := p * p; q <= 1<<30/p; q *= p {
+			if primes.IsSquareFree(q) {
+				t.Errorf("IsSquareFree(%d) == true, want false", q)
+			}
+		}
+		// Products of primes are squarefree unless a prime is repeated
+		if i > 0 {
+			n := p * ps[i-1] * ps[i/2]
+			if want := i-1 != i/2; primes.IsSquareFree(n) != want {
+				t.Errorf("IsSquareFree(%d) == %v, want %v", n, !want, want)
+			}
+		}
+	}
+}
+
+func TestIsSmooth(t *testing.T) {
+	cases := []struct {
+		n, bound int
+		want     bool
+	}{
+		{-6, 5, false},
+		{0, 5, false},
+		{1, 0, true},
+		{2, 1, false},
+		{2, 2, true},
+		{360, 5, true},
+		{360, 4, false},
+		{42, 5, false},
+		{1 << 30, 2, true},
+		{9973 * 9967, 9967, false},
+		{9973 * 9967, 9973, true},
+	}
+	for _, c := range cases {
+		if s := primes.IsSmooth(c.n, c.bound); s != c.want {
+			t.Errorf("IsSmooth(%d,%d) == %v, want %v", c.n, c.bound, s, c.want)
+		}
+	}
+
+	// Count the 7-smooth (humble) numbers up to 1000; see
+	// https://oeis.org/A002473
+	count := 0
+	for n := 1; n <= 1000; n++ {
+		if primes.IsSmooth(n, 7) {
+			count++
+		}
+	}
+	if count != 141 {
+		t.Errorf("found %d 7-smooth numbers in [1,1000], want 141", count)
+	}
+}
+
+func TestTotientChainLength(t *testing.T) {
+	cases := []struct {
+		n    int
+		want int
+	}{
+		{-1, 0},
+		{0, 0},
+		{1, 0},
+		{1 << 30, 30},
+		{3 << 20, 21},
+	}
+	for _, c := range cases {
+		l := primes.TotientChainLength(c.n)
+		if l != c.want {
+			t.Errorf("TotientChainLength(%d) == %d, want %d", c.n, l, c.want)
+		}
+	}
+
+	// See https://oeis.org/A003434
+	want := []int{0, 1, 2, 2, 3, 2, 3, 3, 3, 3, 4, 3, 4, 3, 4, 4, 5, 3, 4, 4}
+	for i, w := range want {
+		n := i + 1
+		if l := primes.TotientChainLength(n); l != w {
+			t.Errorf("TotientChainLength(%d) == %d, want %d", n, l, w)
+		}
+	}
+
+	// Follow the chain to check that it ends at 1 after that many steps
+	for n := 1; n <= 5000; n++ {
+		m := n
+		for i := primes.TotientChainLength(n); i > 0; i-- {
+			m = primes.EulerPhi(m)
+		}
+		if m != 1 {
+			t.Errorf("TotientChainLength(%d) does not end the chain at 1", n)
+		}
+	}
+}
+
+func TestSumOverPrimes(t *testing.T) {
+	one := func(p int) float64 { return 1 }
+	for _, n := range []int{-1, 0, 1, 2, 10, 1000, 100000} {
+		// Summing 1 over the primes counts them
+		want := primes.PiExact(n)
+		if sum := primes.SumOverPrimes(n, one); sum != float64(want) {
+			t.Errorf("SumOverPrimes(%d,1) == %f, want %d", n, sum, want)
+		}
+	}
+
+	// The sum of the reciprocals matches a plain loop over the primes and
+	// approaches log(log(n)) plus the Meissel-Mertens constant
+	const meisselMertens = 0.2614972128476427837554268386
+	reciprocal := func(p int) float64 { return 1 / float64(p) }
+	for _, n := range []int{100, 10000, 1000000} {
+		want := 0.0
+		for _, p := range primes.Sieve(n) {
+			want += 1 / float64(p)
+		}
+		sum := primes.SumOverPrimes(n, reciprocal)
+		if sum != want {
+			t.Errorf("SumOverPrimes(%d,1/p) == %f, want %f", n, sum, want)
+		}
+		if approx := math.Log(math.Log(float64(n))) + meisselMertens; math.Abs(sum-approx) > 0.02 {
+			t.Errorf("SumOverPrimes(%d,1/p) == %f, want about %f", n, sum, approx)
+		}
+	}
+
+	// Chebyshev's function theta(n) is close to n
+	theta := func(p int) float64 { return math.Log(float64(p)) }
+	for _, n := range []int{10000, 1000000} {
+		if sum := primes.SumOverPrimes(n, theta); math.Abs(sum-float64(n)) > 0.02*float64(n) {
+			t.Errorf("SumOverPrimes(%d,log(p)) == %f, want about %d", n, sum, n)
+		}
+	}
+
+	// The prime zeta function P(2) is about 0.4522474200410654985065
+	square := func(p int) float64 { return 1 / float64(p) / float64(p) }
+	if sum := primes.SumOverPrimes(1000000, square); math.Abs(sum-0.4522474200410654985065) > 1e-6 {
+		t.Errorf("SumOverPrimes(1000000,1/p^2) == %f, want about 0.452247", sum)
+	}
+}
+
+func TestSumPrimes(t *testing.T) {
+	// See https://oeis.org/A034387
+	cases := []struct {
+		n    int
+		want int64
+	}{
+		{-1, 0},
+		{0, 0},
+		{1, 0},
+		{2, 2},
+		{3, 5},
+		{10, 17},
+		{100, 1060},
+		{10000, 5736396},
+		{225286, 2147431330},
+		{1999999, 142913828922},
+		{2000000, 142913828922},
+	}
+	for _, c := range cases {
+		if int64(int(c.want)) != c.want {
+			// The sum does not fit in an int on this platform
+			continue
+		}
+		if sum := primes.SumPrimes(c.n); int64(sum) != c.want {
+			t.Errorf("SumPrimes(%d) == %d, want %d", c.n, sum, c.want)
+		}
+	}
+
+	// Check against a plain sum of the sieve
+	for _, n := range []int{1000, 54321, 100000} {
+		want := 0
+		for _, p := range primes.Sieve(n) {
+			want += p
+		}
+		if sum := primes.SumPrimes(n); sum != want {
+			t.Errorf("SumPrimes(%d) == %d, want %d", n, sum, want)
+		}
+	}
+}
+
+func TestSumFirstNPrimes(t *testing.T) {
+	// See https://oeis.org/A007504
+	cases := []struct {
+		n    int
+		want int64
+	}{
+		{-1, 0},
+		{0, 0},
+		{1, 2},
+		{2, 5},
+		{10, 129},
+		{100, 24133},
+		{1000, 3682913},
+		{1000000, 7472966967499},
+	}
+	for _, c := range cases {
+		if sum, ok := primes.SumFirstNPrimes(c.n); sum != c.want || !ok {
+			t.Errorf("SumFirstNPrimes(%d) == (%d,%v), want (%d,true)", c.n, sum, ok, c.want)
+		}
+	}
+
+	// Check against the sums of the primes given by Sieve
+	want := int64(0)
+	for i, p := range primes.Sieve(20000) {
+		want += int64(p)
+		if i%97 == 0 {
+			if sum, ok := primes.SumFirstNPrimes(i + 1); sum != want || !ok {
+				t.Errorf("SumFirstNPrimes(%d) == (%d,%v), want (%d,true)", i+1, sum, ok, want)
+			}
+		}
+	}
+}
+
+func TestZetaEulerProduct(t *testing.T) {
+	for _, n := range []int{-1, 0, 1} {
+		if z := primes.ZetaEulerProduct(2, n); z != 1 {
+			t.Errorf("ZetaEulerProduct(2,%d) == %f, want 1", n, z)
+		}
+	}
+	if z, want := primes.ZetaEulerProduct(2, 5), 4.0/3*9/8*25/24; math.Abs(z-want) > 1e-12 {
+		t.Errorf("ZetaEulerProduct(2,5) == %f, want %f", z, want)
+	}
+
+	// The partial products approach zeta(s) from below as n grows (up to
+	// rounding errors)
+	zetas := []struct {
+		s    float64
+		zeta float64
+	}{
+		{2, math.Pi * math.Pi / 6},
+		{3, 1.2020569031595942853997},
+		{4, math.Pow(math.Pi, 4) / 90},
+	}
+	for _, z := range zetas {
+		prev := 0.0
+		for _, n := range []int{10, 100, 1000, 10000, 100000, 1000000} {
+			p := primes.ZetaEulerProduct(z.s, n)
+			if p < prev || p > z.zeta+1e-12 || z.zeta-p > 2/(float64(n)*math.Log(float64(n))) {
+				t.Errorf("ZetaEulerProduct(%g,%d) == %.10f, want about %.10f", z.s, n, p, z.zeta)
+			}
+			prev = p
+		}
+	}
+}
+
+func TestAverageOmega(t *testing.T) {
+	cases := []struct {
+		n    int
+		want float64
+	}{
+		{-1, 0},
+		{0, 0},
+		{1, 0},
+		{2, 1},
+		{6, 6.0 / 5},
+		{10, 11.0 / 9},
+	}
+	for _, c := range cases {
+		if avg := primes.AverageOmega(c.n); math.Abs(avg-c.want) > 1e-12 {
+			t.Errorf("AverageOmega(%d) == %f, want %f", c.n, avg, c.want)
+		}
+	}
+
+	// Check against the identity sum(omega(k)) == sum(n/p) over primes p <= n
+	for _, n := range []int{100, 1000, 12345, 100000} {
+		sum := 0
+		for _, p := range primes.Sieve(n) {
+			sum += n / p
+		}
+		want := float64(sum) / float64(n-1)
+		if avg := primes.AverageOmega(n); math.Abs(avg-want) > 1e-12 {
+			t.Errorf("AverageOmega(%d) == %f, want %f", n, avg, want)
+		}
+	}
+
+	// The average should be close to log(log(n)) and, more precisely,
+	// to log(log(n)) plus the Meissel-Mertens constant
+	const n = 1000000
+	const mertens = 0.2614972128
+	avg := primes.AverageOmega(n)
+	lnln := math.Log(math.Log(n))
+	if math.Abs(avg-lnln) > 0.3 || math.Abs(avg-lnln-mertens) > 0.05 {
+		t.Errorf("AverageOmega(%d) == %f, want about %f+%f", n, avg, lnln, mertens)
+	}
+}
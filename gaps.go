@@ -0,0 +1,196 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+import "math"
+
+// PrimeGaps returns the differences between consecutive primes less than or
+// equal to n; for example, the primes up to 10 are [2 3 5 7], so
+// PrimeGaps(10) returns [1 2 2].
+// The gaps are derived directly from the output of Sieve(n).
+// If n is less than 3, it returns an empty list.
+// See https://en.wikipedia.org/wiki/Prime_gap for details.
+func PrimeGaps(n int) []int {
+	ps := Sieve(n)
+	if len(ps) < 2 {
+		return []int{}
+	}
+	gs := make([]int, len(ps)-1)
+	for i := range gs {
+		gs[i] = ps[i+1] - ps[i]
+	}
+	return gs
+}
+
+// Gap returns the gap that follows the prime p, that is the difference
+// between the next prime and p; for example, Gap(7) == 4 since the next
+// prime is 11.
+// It finds the next prime with NextPrimeInclusive, without listing any
+// other gaps, and checks p itself with IsPrimeMR, so it is fast for any p.
+// If p is not prime, it returns -1; if the next prime does not fit in an
+// int, it returns 0.
+// See https://en.wikipedia.org/wiki/Prime_gap for details.
+func Gap(p int) int {
+	if !IsPrimeMR(int64(p)) {
+		return -1
+	}
+	if p == math.MaxInt {
+		return 0
+	}
+	q := NextPrimeInclusive(p + 1)
+	if q == 0 {
+		return 0
+	}
+	return q - p
+}
+
+// MaxPrimeGap returns the largest difference between consecutive primes less
+// than or equal to n and the prime at which the first such gap starts.
+// If n is less than 3, it returns (0,0).
+func MaxPrimeGap(n int) (gap, startPrime int) {
+	ps := Sieve(n)
+	for i := 1; i < len(ps); i++ {
+		if g := ps[i] - ps[i-1]; g > gap {
+			gap, startPrime = g, ps[i-1]
+		}
+	}
+	return
+}
+
+// PrimeGapGraph groups the gaps between consecutive primes in [lo,hi] by
+// size: it returns a map from each gap size to the list of primes p, in
+// increasing order, such that p and p+gap are consecutive primes in
+// [lo,hi]; for example, PrimeGapGraph(0,30) maps 2 to [3 5 11 17] and 6
+// to [23].
+// The primes are found with SieveRange, so a band of large numbers can be
+// explored without sieving all the numbers below lo.
+// If there are fewer than two primes in [lo,hi], it returns an empty map.
+func PrimeGapGraph(lo, hi int) map[int][]int {
+	g := make(map[int][]int)
+	ps := SieveRange(lo, hi)
+	for i := 1; i < len(ps); i++ {
+		gap := ps[i] - ps[i-1]
+		g[gap] = append(g[gap], ps[i-1])
+	}
+	return g
+}
+
+// HighMeritGaps returns the gaps between consecutive primes in [lo,hi] whose
+// merit exceeds minMerit, as triples [gap lowPrime highPrime] in increasing
+// order of lowPrime.
+// The merit of a gap g following the prime p is g/log(p), which measures
+// how much longer the gap is than the average gap around p; gaps with a
+// merit above 10 are rare and the largest known merit is below 42.
+// The primes are found with SieveRange, so a band of large numbers can be
+// searched without sieving all the numbers below lo.
+// See https://en.wikipedia.org/wiki/Prime_gap#Numerical_results for
+// details.
+func HighMeritGaps(lo, hi int, minMerit float64) [][3]int {
+	gs := [][3]int{}
+	ps := SieveRange(lo, hi)
+	for i := 1; i < len(ps); i++ {
+		p, q := ps[i-1], ps[i]
+		if float64(q-p)/math.Log(float64(p)) > minMerit {
+			gs = append(gs, [3]int{q - p, p, q})
+		}
+	}
+	return gs
+}
+
+// gapWindow is the width of the ranges sieved one after the other by
+// FirstGapAtLeast
+const gapWindow = 1 << 20
+
+// FirstGapAtLeast returns the first gap of at least minGap between
+// consecutive primes p and p+gap such that p >= start, together with p.
+// It runs a segmented sieve of Eratosthenes forward from start, one window
+// of gapWindow numbers at a time, so it can hunt for large gaps in a high
+// band without sieving all the numbers below start; the base primes up to
+// the square root of the end of the window are sieved only once and
+// extended as the windows move on.
+// If no such gap is found before the primes run out of the range of an int,
+// it returns (0,0).
+// See https://en.wikipedia.org/wiki/Prime_gap for details.
+func FirstGapAtLeast(start, minGap int) (lowPrime, gap int) {
+	prev, lo := 0, start
+	if start <= 2 {
+		prev, lo = 2, 3
+	}
+	var ps []int64  // the odd primes up to limit
+	var limit int64 // the largest number checked for ps
+	for {
+		hi := math.MaxInt
+		if lo <= math.MaxInt-gapWindow {
+			hi = lo + gapWindow - 1
+		}
+		if r := isqrt64(int64(hi)); ps == nil {
+			ps, limit = oddPrimesUpTo(r), r
+		} else if r > limit {
+			// Extend the base primes with some room to spare, so that
+			// they need to be extended only once in a while; the primes
+			// up to limit are enough to sieve them up to limit*limit
+			next := r + r/16
+			if next > limit*limit {
+				next = limit * limit
+			}
+			if m := isqrt64(math.MaxInt); next > m {
+				next = m
+			}
+			from := limit + 1
+			if from%2 == 0 {
+				from++
+			}
+			sieveSegmentsWith(ps, from, next, func(base int64, a []bool) bool {
+				for i, composite := range a {
+					if !composite {
+						ps = append(ps, base+2*int64(i))
+					}
+				}
+				return true
+			})
+			limit = next
+		}
+		odd := lo
+		if odd%2 == 0 {
+			odd++
+		}
+		sieveSegmentsWith(ps, int64(odd), int64(hi), func(base int64, a []bool) bool {
+			for i, composite := range a {
+				if composite {
+					continue
+				}
+				p := int(base) + 2*i
+				if prev != 0 && p-prev >= minGap {
+					lowPrime, gap = prev, p-prev
+					return false
+				}
+				prev = p
+			}
+			return true
+		})
+		if gap > 0 || hi == math.MaxInt {
+			return
+		}
+		lo = hi + 1
+	}
+}
@@ -0,0 +1,266 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+// maximalGaps lists the maximal prime gaps below 10^6 and the primes at which
+// they start (see https://oeis.org/A002386 and https://oeis.org/A005250)
+var maximalGaps = []struct {
+	gap, start int
+}{
+	{1, 2}, {2, 3}, {4, 7}, {6, 23}, {8, 89}, {14, 113}, {18, 523},
+	{20, 887}, {22, 1129}, {34, 1327}, {36, 9551}, {44, 15683},
+	{52, 19609}, {72, 31397}, {86, 155921}, {96, 360653}, {112, 370261},
+	{114, 492113},
+}
+
+func TestPrimeGaps(t *testing.T) {
+	cases := []struct {
+		n    int
+		want []int
+	}{
+		{-1, []int{}},
+		{0, []int{}},
+		{2, []int{}},
+		{3, []int{1}},
+		{10, []int{1, 2, 2}},
+		{30, []int{1, 2, 2, 4, 2, 4, 2, 4, 6}},
+	}
+	for _, c := range cases {
+		gs := primes.PrimeGaps(c.n)
+		if !reflect.DeepEqual(gs, c.want) {
+			t.Errorf("PrimeGaps(%d) == %v, want %v", c.n, gs, c.want)
+		}
+	}
+
+	// The gaps must add up to the distance between the first and last prime
+	const n = 1000000
+	sum := 2
+	for _, g := range primes.PrimeGaps(n) {
+		sum += g
+	}
+	if sum != 999983 {
+		t.Errorf("2+sum(PrimeGaps(%d)) == %d, want %d", n, sum, 999983)
+	}
+}
+
+func TestGap(t *testing.T) {
+	cases := []struct {
+		p    int64
+		want int
+	}{
+		{-7, -1},
+		{0, -1},
+		{1, -1},
+		{2, 1},
+		{3, 2},
+		{4, -1},
+		{7, 4},
+		{23, 6},
+		{9973, 34},
+		{10007, 2},
+		{10009, 28},
+		{10011, -1},
+		{1327, 34},
+		{1000003, 30},
+		{2147483629, 18},
+		{math.MaxInt32 + 2, -1},
+		{9223372036854775783, 0},
+	}
+	for _, c := range cases {
+		p := int(c.p)
+		if int64(p) != c.p {
+			// p does not fit in an int on this platform
+			continue
+		}
+		if g := primes.Gap(p); g != c.want {
+			t.Errorf("Gap(%d) == %d, want %d", c.p, g, c.want)
+		}
+	}
+
+	// Check against PrimeGaps
+	ps := primes.Sieve(100000)
+	for i, g := range primes.PrimeGaps(100000) {
+		if gap := primes.Gap(ps[i]); gap != g {
+			t.Errorf("Gap(%d) == %d, want %d", ps[i], gap, g)
+		}
+	}
+}
+
+func TestMaxPrimeGap(t *testing.T) {
+	for _, n := range []int{-1, 0, 1, 2} {
+		if gap, start := primes.MaxPrimeGap(n); gap != 0 || start != 0 {
+			t.Errorf("MaxPrimeGap(%d) == (%d,%d), want (0,0)", n, gap, start)
+		}
+	}
+	for i, mg := range maximalGaps {
+		// The gap is found as soon as n reaches its end
+		n := mg.start + mg.gap
+		if gap, start := primes.MaxPrimeGap(n); gap != mg.gap || start != mg.start {
+			t.Errorf("MaxPrimeGap(%d) == (%d,%d), want (%d,%d)", n, gap, start, mg.gap, mg.start)
+		}
+		// ...and the previous maximal gap is still the largest until then
+		if i > 0 {
+			prev := maximalGaps[i-1]
+			if gap, start := primes.MaxPrimeGap(n - 1); gap != prev.gap || start != prev.start {
+				t.Errorf("MaxPrimeGap(%d) == (%d,%d), want (%d,%d)", n-1, gap, start, prev.gap, prev.start)
+			}
+		}
+	}
+	if gap, start := primes.MaxPrimeGap(1000000); gap != 114 || start != 492113 {
+		t.Errorf("MaxPrimeGap(%d) == (%d,%d), want (%d,%d)", 1000000, gap, start, 114, 492113)
+	}
+}
+
+func TestPrimeGapGraph(t *testing.T) {
+	cases := []struct {
+		lo, hi int
+		want   map[int][]int
+	}{
+		{10, 0, map[int][]int{}},
+		{0, 2, map[int][]int{}},
+		{24, 28, map[int][]int{}},
+		{0, 3, map[int][]int{1: {2}}},
+		{0, 30, map[int][]int{1: {2}, 2: {3, 5, 11, 17}, 4: {7, 13, 19}, 6: {23}}},
+		{1327, 1361, map[int][]int{34: {1327}}},
+	}
+	for _, c := range cases {
+		if g := primes.PrimeGapGraph(c.lo, c.hi); !reflect.DeepEqual(g, c.want) {
+			t.Errorf("PrimeGapGraph(%d,%d) == %v, want %v", c.lo, c.hi, g, c.want)
+		}
+	}
+
+	// The gaps of 2 start at the first members of the twin prime pairs and
+	// all the gaps add up to the distance between the extreme primes
+	lo, hi := 1000000, 1100000
+	var twins []int
+	for _, tp := range primes.TwinPrimes(hi) {
+		if tp[0] >= lo {
+			twins = append(twins, tp[0])
+		}
+	}
+	g := primes.PrimeGapGraph(lo, hi)
+	if !reflect.DeepEqual(g[2], twins) {
+		t.Errorf("PrimeGapGraph(%d,%d)[2] has %d primes, want %d", lo, hi, len(g[2]), len(twins))
+	}
+	ps := primes.SieveRange(lo, hi)
+	sum := 0
+	for gap, starts := range g {
+		sum += gap * len(starts)
+	}
+	if want := ps[len(ps)-1] - ps[0]; sum != want {
+		t.Errorf("PrimeGapGraph(%d,%d) gaps add up to %d, want %d", lo, hi, sum, want)
+	}
+}
+
+func TestHighMeritGaps(t *testing.T) {
+	cases := []struct {
+		lo, hi   int
+		minMerit float64
+		want     [][3]int
+	}{
+		{10, 0, 0, [][3]int{}},
+		{0, 30, 3, [][3]int{}},
+		{0, 30, 2, [][3]int{{4, 7, 11}}},
+		{0, 30, 1.5, [][3]int{{2, 3, 5}, {4, 7, 11}, {4, 13, 17}, {6, 23, 29}}},
+		// The maximal gap of 34 after 1327 has a merit of 4.72
+		{1000, 2000, 4.5, [][3]int{{34, 1327, 1361}}},
+	}
+	for _, c := range cases {
+		if gs := primes.HighMeritGaps(c.lo, c.hi, c.minMerit); !reflect.DeepEqual(gs, c.want) {
+			t.Errorf("HighMeritGaps(%d,%d,%g) == %v, want %v", c.lo, c.hi, c.minMerit, gs, c.want)
+		}
+	}
+
+	// A low threshold lets most gaps through, while a high one lets through
+	// only a few of them, if any
+	lo, hi := 2, 2000000
+	ngaps := len(primes.SieveRange(lo, hi)) - 1
+	if gs := primes.HighMeritGaps(lo, hi, 0.1); len(gs) < ngaps/2 {
+		t.Errorf("HighMeritGaps(%d,%d,0.1) returned %d gaps, want most of %d", lo, hi, len(gs), ngaps)
+	}
+	if gs := primes.HighMeritGaps(lo, hi, 20); len(gs) != 0 {
+		t.Errorf("HighMeritGaps(%d,%d,20) == %v, want no gaps", lo, hi, gs)
+	}
+	gs := primes.HighMeritGaps(lo, hi, 8)
+	if len(gs) == 0 || len(gs) > 100 {
+		t.Errorf("HighMeritGaps(%d,%d,8) returned %d gaps, want a few", lo, hi, len(gs))
+	}
+	for _, g := range gs {
+		gap, p, q := g[0], g[1], g[2]
+		if q-p != gap || float64(gap)/math.Log(float64(p)) <= 8 ||
+			!primes.IsPrime(p) || primes.NextPrimeInclusive(p+1) != q {
+			t.Errorf("HighMeritGaps(%d,%d,8) includes %v", lo, hi, g)
+		}
+	}
+}
+
+func TestFirstGapAtLeast(t *testing.T) {
+	cases := []struct {
+		start, minGap int
+		lowPrime, gap int
+	}{
+		{-10, 0, 2, 1},
+		{0, 1, 2, 1},
+		{3, 1, 3, 2},
+		{4, 2, 5, 2},
+		{8, 4, 13, 4},
+		{0, 3, 7, 4},
+		{1000000, 1, 1000003, 30},
+		// Found after many windows, extending the base primes on the way
+		// (see https://oeis.org/A002386)
+		{0, 200, 20831323, 210},
+		{20831324, 200, 47326693, 220},
+	}
+	for _, c := range cases {
+		lowPrime, gap := primes.FirstGapAtLeast(c.start, c.minGap)
+		if lowPrime != c.lowPrime || gap != c.gap {
+			t.Errorf("FirstGapAtLeast(%d,%d) == (%d,%d), want (%d,%d)",
+				c.start, c.minGap, lowPrime, gap, c.lowPrime, c.gap)
+		}
+	}
+
+	// Starting from 0, the first gaps found are the maximal ones
+	for _, mg := range maximalGaps {
+		if lowPrime, gap := primes.FirstGapAtLeast(0, mg.gap); lowPrime != mg.start || gap != mg.gap {
+			t.Errorf("FirstGapAtLeast(0,%d) == (%d,%d), want (%d,%d)", mg.gap, lowPrime, gap, mg.start, mg.gap)
+		}
+	}
+
+	// Hunt for a large gap in a high band: its endpoints must be
+	// consecutive primes
+	for _, start := range []int{1000000, 1 << 30} {
+		lowPrime, gap := primes.FirstGapAtLeast(start, 100)
+		if lowPrime < start || gap < 100 || !primes.IsPrime(lowPrime) ||
+			primes.NextPrimeInclusive(lowPrime+1) != lowPrime+gap {
+			t.Errorf("FirstGapAtLeast(%d,100) == (%d,%d)", start, lowPrime, gap)
+		}
+	}
+}
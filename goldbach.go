@@ -0,0 +1,84 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+// Goldbach returns two primes a <= b such that a+b == n, for any even n
+// greater than 2; of all such pairs, it returns the one with the smallest a,
+// which it finds by testing the primes a from 2 upward with IsPrime.
+// If n is odd or less than or equal to 2, ok is false.
+// Goldbach's conjecture states that every even n greater than 2 is the sum
+// of two primes; it has been verified for all n up to 4*10^18.
+// See https://en.wikipedia.org/wiki/Goldbach%27s_conjecture for details.
+func Goldbach(n int) (a, b int, ok bool) {
+	if n <= 2 || n%2 != 0 {
+		return 0, 0, false
+	}
+	if n == 4 {
+		return 2, 2, true
+	}
+	// For n > 4, a must be odd
+	for a = 3; a <= n/2; a += 2 {
+		if IsPrime(a) && IsPrime(n-a) {
+			return a, n - a, true
+		}
+	}
+	// Goldbach's conjecture would be false!
+	return 0, 0, false
+}
+
+// GoldbachCount returns the number of ways n can be written as the sum of
+// two primes p <= q; for example, GoldbachCount(10) == 2 because
+// 10 == 3+7 == 5+5.
+// Goldbach's conjecture states that GoldbachCount(n) > 0 for every even
+// n greater than 2.
+// See https://en.wikipedia.org/wiki/Goldbach%27s_conjecture for details.
+func GoldbachCount(n int) int {
+	s := NewPrimeSet(n)
+	c := 0
+	for _, p := range s.Slice() {
+		if p > n-p {
+			break
+		}
+		if s.Contains(n - p) {
+			c++
+		}
+	}
+	return c
+}
+
+// GoldbachOrderedCount returns the number of ordered pairs of primes (p,q)
+// such that p+q == n, so that (3,7) and (7,3) are counted separately;
+// for example, GoldbachOrderedCount(10) == 3 because 10 == 3+7 == 5+5 == 7+3.
+func GoldbachOrderedCount(n int) int {
+	s := NewPrimeSet(n)
+	c := 0
+	for _, p := range s.Slice() {
+		if p > n-2 {
+			break
+		}
+		if s.Contains(n - p) {
+			c++
+		}
+	}
+	return c
+}
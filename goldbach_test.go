@@ -0,0 +1,123 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestGoldbach(t *testing.T) {
+	cases := []struct {
+		n    int
+		a, b int
+		ok   bool
+	}{
+		{-4, 0, 0, false},
+		{0, 0, 0, false},
+		{2, 0, 0, false},
+		{3, 0, 0, false},
+		{4, 2, 2, true},
+		{6, 3, 3, true},
+		{8, 3, 5, true},
+		{9, 0, 0, false},
+		{28, 5, 23, true},
+		{100, 3, 97, true},
+		{98, 19, 79, true},
+		{1000000, 17, 999983, true},
+	}
+	for _, c := range cases {
+		a, b, ok := primes.Goldbach(c.n)
+		if a != c.a || b != c.b || ok != c.ok {
+			t.Errorf("Goldbach(%d) == (%d,%d,%v), want (%d,%d,%v)", c.n, a, b, ok, c.a, c.b, c.ok)
+		}
+	}
+
+	// Every even number up to 10^5 has a decomposition
+	for n := 4; n <= 100000; n += 2 {
+		a, b, ok := primes.Goldbach(n)
+		if !ok || a > b || a+b != n || !primes.IsPrime(a) || !primes.IsPrime(b) {
+			t.Errorf("Goldbach(%d) == (%d,%d,%v)", n, a, b, ok)
+		}
+	}
+}
+
+func TestGoldbachCount(t *testing.T) {
+	cases := []struct {
+		n       int
+		count   int
+		ordered int
+	}{
+		{-4, 0, 0},
+		{0, 0, 0},
+		{3, 0, 0},
+		{4, 1, 1},
+		{5, 1, 2},
+		{6, 1, 1},
+		{10, 2, 3},
+		{11, 0, 0},
+		{100, 6, 12},
+		{1000, 28, 56},
+	}
+	for _, c := range cases {
+		if n := primes.GoldbachCount(c.n); n != c.count {
+			t.Errorf("GoldbachCount(%d) == %d, want %d", c.n, n, c.count)
+		}
+		if n := primes.GoldbachOrderedCount(c.n); n != c.ordered {
+			t.Errorf("GoldbachOrderedCount(%d) == %d, want %d", c.n, n, c.ordered)
+		}
+	}
+
+	// Check against a brute-force count over all pairs
+	const max = 10000
+	isPrime := make([]bool, max+1)
+	for _, p := range baselineSieve(max) {
+		isPrime[p] = true
+	}
+	for n := 4; n <= max; n += 2 {
+		count, ordered := 0, 0
+		for p := 2; p <= n-2; p++ {
+			if isPrime[p] && isPrime[n-p] {
+				ordered++
+				if p <= n-p {
+					count++
+				}
+			}
+		}
+		if c := primes.GoldbachCount(n); c != count {
+			t.Errorf("GoldbachCount(%d) == %d, want %d", n, c, count)
+		}
+		if c := primes.GoldbachOrderedCount(n); c != ordered {
+			t.Errorf("GoldbachOrderedCount(%d) == %d, want %d", n, c, ordered)
+		}
+		// Every unordered pair but p == q shows up twice as an ordered pair
+		b := 0
+		if isPrime[n/2] {
+			b = 1
+		}
+		if c, u := primes.GoldbachOrderedCount(n), primes.GoldbachCount(n); c != 2*u-b {
+			t.Errorf("GoldbachOrderedCount(%d) == %d, want 2*GoldbachCount(%d)-%d == %d", n, c, n, b, 2*u-b)
+		}
+	}
+}
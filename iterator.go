@@ -0,0 +1,129 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+import (
+	"iter"
+	"math"
+)
+
+// PrimeIterator generates the prime numbers one at a time, in increasing
+// order and without any upper bound: each call to Next returns the next
+// prime, starting from 2.
+// It sieves the numbers in windows with a segmented sieve of Eratosthenes,
+// moving on to the next window when it runs out of primes; a window is
+// about 16*sqrt(p) numbers wide around the current prime p, so the memory
+// used stays small while the cost of finding the base primes for each
+// window remains a fraction of the cost of sieving it.
+// Unlike a generator running in a goroutine and sending the primes over a
+// channel, a PrimeIterator needs no cleanup: it can simply be dropped.
+// The zero value for PrimeIterator is ready to use.
+// A PrimeIterator is not safe for concurrent use.
+type PrimeIterator struct {
+	hi int   // all the primes <= hi have been sieved
+	ps []int // the primes sieved in the current window
+	i  int   // the index in ps of the next prime to return
+}
+
+// Next returns the next prime number.
+// Once the primes run out of the range of an int, it returns 0.
+func (it *PrimeIterator) Next() int {
+	for it.i == len(it.ps) {
+		if it.hi == math.MaxInt {
+			return 0
+		}
+		it.fill()
+	}
+	p := it.ps[it.i]
+	it.i++
+	return p
+}
+
+// Reset restarts the iterator, so that the next call to Next returns 2.
+func (it *PrimeIterator) Reset() {
+	*it = PrimeIterator{}
+}
+
+// fill sieves the next window of numbers into it.ps.
+func (it *PrimeIterator) fill() {
+	lo := it.hi + 1
+	width := 16 * isqrt(lo)
+	if width < 2*segmentSize {
+		width = 2 * segmentSize
+	}
+	hi := math.MaxInt
+	if lo <= math.MaxInt-width {
+		hi = lo + width - 1
+	}
+	it.ps, it.i = it.ps[:0], 0
+	if lo <= 2 {
+		it.ps = append(it.ps, 2)
+	}
+	sieveSegments(int64(lo), int64(hi), func(base int64, a []bool) bool {
+		for i, composite := range a {
+			if !composite {
+				it.ps = append(it.ps, int(base)+2*i)
+			}
+		}
+		return true
+	})
+	it.hi = hi
+}
+
+// All returns an iterator over the prime numbers less than or equal to n,
+// in increasing order, for use in a range loop:
+//
+//	for p := range primes.All(100) {
+//		...
+//	}
+//
+// The primes are sieved in windows as the loop goes on, like with a
+// PrimeIterator, so breaking out of the loop early saves the work of
+// sieving the numbers up to n.
+func All(n int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for p := range AllFrom(2) {
+			if p > n || !yield(p) {
+				return
+			}
+		}
+	}
+}
+
+// AllFrom returns an iterator over the prime numbers greater than or equal
+// to lo, in increasing order and without any upper bound other than the
+// range of an int; the loop over it must be ended with a break.
+// Like All, it sieves the numbers in windows as the loop goes on.
+func AllFrom(lo int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		it := PrimeIterator{hi: lo - 1}
+		if lo < 2 {
+			it.hi = 1
+		}
+		for p := it.Next(); p != 0; p = it.Next() {
+			if !yield(p) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,121 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestPrimeIterator(t *testing.T) {
+	// The first 500 primes go up to 3571
+	first := primes.Sieve(3571)
+	var it primes.PrimeIterator
+	for i, want := range first {
+		if p := it.Next(); p != want {
+			t.Fatalf("PrimeIterator.Next() #%d == %d, want %d", i+1, p, want)
+		}
+	}
+
+	// Reset restarts from 2
+	it.Reset()
+	for i, want := range first {
+		if p := it.Next(); p != want {
+			t.Fatalf("PrimeIterator.Next() #%d after Reset == %d, want %d", i+1, p, want)
+		}
+	}
+
+	// The primes keep coming across many windows
+	it.Reset()
+	for i, want := range primes.Sieve(3000000) {
+		if p := it.Next(); p != want {
+			t.Fatalf("PrimeIterator.Next() #%d == %d, want %d", i+1, p, want)
+		}
+	}
+}
+
+func TestAll(t *testing.T) {
+	for _, n := range []int{-1, 0, 1, 2, 3, 100, 1000000} {
+		ps := []int{}
+		for p := range primes.All(n) {
+			ps = append(ps, p)
+		}
+		if want := primes.Sieve(n); !reflect.DeepEqual(ps, want) {
+			t.Errorf("All(%d) yields %d primes, want %d", n, len(ps), len(want))
+		}
+	}
+
+	// Breaking early stops the iteration without sieving up to n, which
+	// would take minutes and gigabytes here
+	ps := []int{}
+	for p := range primes.All(math.MaxInt) {
+		if p > 20 {
+			break
+		}
+		ps = append(ps, p)
+	}
+	if want := []int{2, 3, 5, 7, 11, 13, 17, 19}; !reflect.DeepEqual(ps, want) {
+		t.Errorf("All(math.MaxInt) yields %v until 20, want %v", ps, want)
+	}
+}
+
+func TestAllFrom(t *testing.T) {
+	cases := []struct {
+		lo   int
+		want []int
+	}{
+		{-10, []int{2, 3, 5, 7, 11}},
+		{2, []int{2, 3, 5, 7, 11}},
+		{3, []int{3, 5, 7, 11, 13}},
+		{24, []int{29, 31, 37, 41, 43}},
+		{1000000, []int{1000003, 1000033, 1000037, 1000039, 1000081}},
+		{1 << 30, []int{1073741827, 1073741831, 1073741833, 1073741839, 1073741843}},
+	}
+	for _, c := range cases {
+		ps := []int{}
+		for p := range primes.AllFrom(c.lo) {
+			ps = append(ps, p)
+			if len(ps) == len(c.want) {
+				break
+			}
+		}
+		if !reflect.DeepEqual(ps, c.want) {
+			t.Errorf("AllFrom(%d) yields %v, want %v", c.lo, ps, c.want)
+		}
+	}
+
+	// The stream goes on across many windows
+	ps := []int{}
+	for p := range primes.AllFrom(1000000) {
+		if p > 3000000 {
+			break
+		}
+		ps = append(ps, p)
+	}
+	if want := primes.SieveRange(1000000, 3000000); !reflect.DeepEqual(ps, want) {
+		t.Errorf("AllFrom(1000000) yields %d primes up to 3000000, want %d", len(ps), len(want))
+	}
+}
@@ -0,0 +1,154 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+import "fmt"
+
+// ModInverse returns the multiplicative inverse of a modulo m, that is the
+// integer x in [0,m) such that a*x is congruent to 1 modulo m.
+// The inverse exists only if a and m are coprime; if they are not, or if m
+// is not positive, ok is false.
+// The inverse is the coefficient of a in the Bezout identity computed by
+// ExtendedGCD(a,m).
+// See https://en.wikipedia.org/wiki/Modular_multiplicative_inverse for details.
+func ModInverse(a, m int) (x int, ok bool) {
+	if m <= 0 {
+		return 0, false
+	}
+	// Reduce a to [0,m)
+	if a %= m; a < 0 {
+		a += m
+	}
+	g, x, _ := ExtendedGCD(a, m)
+	if g != 1 {
+		return 0, false
+	}
+	if x < 0 {
+		x += m
+	}
+	return x, true
+}
+
+// ModPow returns base^exp modulo m, reduced to [0,m); for example,
+// ModPow(4,13,497) == 445.
+// It uses binary exponentiation, which takes O(log(exp)) multiplications,
+// with 128-bit intermediate products, so it never overflows.
+// Negative bases are reduced modulo m first, so ModPow(-2,3,5) == 2.
+// ModPow panics if exp is negative or m is not positive; use ModInverse to
+// compute negative powers.
+// See https://en.wikipedia.org/wiki/Modular_exponentiation for details.
+func ModPow(base, exp, m int) int {
+	if exp < 0 || m <= 0 {
+		panic(fmt.Sprintf("primes: ModPow(%d,%d,%d) has a negative exponent or a non-positive modulus", base, exp, m))
+	}
+	if base %= m; base < 0 {
+		base += m
+	}
+	return int(powMod64(uint64(base), uint64(exp), uint64(m)))
+}
+
+// Legendre returns the Legendre symbol (a/p) for an odd prime p: it returns
+// 0 if p divides a, 1 if a is a quadratic residue modulo p (i.e. a is
+// congruent to a perfect square modulo p), and -1 otherwise.
+// It implements Euler's criterion, which states that (a/p) is congruent to
+// a^((p-1)/2) modulo p.
+// Legendre panics if p is not an odd prime.
+// See https://en.wikipedia.org/wiki/Legendre_symbol and
+// https://en.wikipedia.org/wiki/Euler%27s_criterion for details.
+func Legendre(a, p int) int {
+	if p < 3 || !IsPrimeMR(int64(p)) {
+		panic(fmt.Sprintf("primes: Legendre modulus %d is not an odd prime", p))
+	}
+	switch ModPow(a, (p-1)/2, p) {
+	case 0:
+		return 0
+	case 1:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// Jacobi returns the Jacobi symbol (a/n) for an odd positive n, that is the
+// product of the Legendre symbols (a/p) over the prime factors p of n,
+// repeated according to their multiplicity; it returns -1, 0, or 1.
+// It is 0 if and only if a and n are not coprime and, unlike the Legendre
+// symbol, (a/n) == 1 does not imply that a is a quadratic residue modulo n.
+// The symbol is computed without factoring n, by repeatedly pulling out
+// the factors of 2 from a and flipping a and n according to the law of
+// quadratic reciprocity, which takes O(log(n)) steps like GCD.
+// Jacobi panics if n is not odd and positive.
+// See https://en.wikipedia.org/wiki/Jacobi_symbol for details.
+func Jacobi(a, n int) int {
+	if n <= 0 || n%2 == 0 {
+		panic(fmt.Sprintf("primes: Jacobi modulus %d is not odd and positive", n))
+	}
+	return jacobi64(int64(a), int64(n))
+}
+
+// jacobi64 returns the Jacobi symbol (a/n) for an odd positive n, like
+// Jacobi, but for int64 values; it does not check n.
+func jacobi64(a, n int64) int {
+	if a %= n; a < 0 {
+		a += n
+	}
+	j := 1
+	for a != 0 {
+		// (2/n) == -1 if and only if n is congruent to 3 or 5 modulo 8
+		for a%2 == 0 {
+			a /= 2
+			if r := n % 8; r == 3 || r == 5 {
+				j = -j
+			}
+		}
+		// (a/n) == -(n/a) if and only if both are congruent to 3 modulo 4
+		a, n = n, a
+		if a%4 == 3 && n%4 == 3 {
+			j = -j
+		}
+		a %= n
+	}
+	if n != 1 {
+		// a and n have a common factor
+		return 0
+	}
+	return j
+}
+
+// SmallestNonResidue returns the smallest positive integer a that is a
+// quadratic nonresidue modulo the odd prime p, that is the smallest a such
+// that Legendre(a,p) == -1; such an a is needed, for example, to initialize
+// the Tonelli-Shanks algorithm for computing square roots modulo p.
+// The result is always a prime and generally very small.
+// If p is not an odd prime (2 has no quadratic nonresidues), ok is false.
+// See https://en.wikipedia.org/wiki/Quadratic_residue for details.
+func SmallestNonResidue(p int) (a int, ok bool) {
+	if p < 3 || !IsPrimeMR(int64(p)) {
+		return 0, false
+	}
+	// Apply Euler's criterion directly rather than through Legendre so
+	// that p is only checked for primality once
+	for a = 2; ModPow(a, (p-1)/2, p) != p-1; a++ {
+	}
+	return a, true
+}
@@ -0,0 +1,290 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestModInverse(t *testing.T) {
+	cases := []struct {
+		a, m int
+		want int
+		ok   bool
+	}{
+		{3, 7, 5, true},
+		{10, 17, 12, true},
+		{-3, 7, 2, true},
+		{10, 7, 5, true},
+		{1, 1, 0, true},
+		{5, 1, 0, true},
+		{0, 7, 0, false},
+		{6, 9, 0, false},
+		{-6, 9, 0, false},
+		{3, 0, 0, false},
+		{3, -7, 0, false},
+	}
+	for _, c := range cases {
+		x, ok := primes.ModInverse(c.a, c.m)
+		if x != c.want || ok != c.ok {
+			t.Errorf("ModInverse(%d,%d) == (%d,%v), want (%d,%v)", c.a, c.m, x, ok, c.want, c.ok)
+		}
+	}
+
+	// An inverse must exist exactly when a and m are coprime
+	for m := 1; m <= 200; m++ {
+		for a := -m; a <= 2*m; a++ {
+			x, ok := primes.ModInverse(a, m)
+			if want := primes.Coprime(a, m); ok != want {
+				t.Errorf("ModInverse(%d,%d) == (%d,%v), want ok=%v", a, m, x, ok, want)
+				continue
+			}
+			if !ok {
+				continue
+			}
+			if x < 0 || x >= m || ((a*x%m)+m)%m != 1%m {
+				t.Errorf("ModInverse(%d,%d) == (%d,true): not an inverse", a, m, x)
+			}
+		}
+	}
+}
+
+func TestModPow(t *testing.T) {
+	cases := []struct {
+		base, exp, m int
+		want         int
+	}{
+		{0, 0, 7, 1},
+		{5, 0, 1, 0},
+		{4, 13, 497, 445},
+		{-2, 3, 5, 2},
+		{2, 10, 1000, 24},
+		{3, 1 << 30, 1, 0},
+		{math.MaxInt32, math.MaxInt32, math.MaxInt32 - 1, 1},
+	}
+	for _, c := range cases {
+		if r := primes.ModPow(c.base, c.exp, c.m); r != c.want {
+			t.Errorf("ModPow(%d,%d,%d) == %d, want %d", c.base, c.exp, c.m, r, c.want)
+		}
+	}
+
+	// Check against repeated multiplication
+	for m := 1; m <= 50; m++ {
+		for base := -m; base <= m; base++ {
+			want := 1 % m
+			for exp := 0; exp <= 2*m; exp++ {
+				if r := primes.ModPow(base, exp, m); r != want {
+					t.Errorf("ModPow(%d,%d,%d) == %d, want %d", base, exp, m, r, want)
+				}
+				want = ((want*base)%m + m) % m
+			}
+		}
+	}
+
+	// By Fermat's little theorem, a^(p-1) is 1 modulo a prime p that does
+	// not divide a, so a^(p-2) is the inverse of a
+	for _, p := range primes.Sieve(2000) {
+		for a := 1; a < p; a += 1 + a/3 {
+			if r := primes.ModPow(a, p-1, p); r != 1 {
+				t.Errorf("ModPow(%d,%d,%d) == %d, want 1", a, p-1, p, r)
+			}
+			x, _ := primes.ModInverse(a, p)
+			if r := primes.ModPow(a, p-2, p); r != x {
+				t.Errorf("ModPow(%d,%d,%d) == %d, want ModInverse(%d,%d) == %d", a, p-2, p, r, a, p, x)
+			}
+		}
+	}
+
+	// ModPow must reject negative exponents and non-positive moduli
+	for _, c := range [][3]int{{2, -1, 7}, {2, 3, 0}, {2, 3, -7}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("ModPow(%d,%d,%d) did not panic", c[0], c[1], c[2])
+				}
+			}()
+			primes.ModPow(c[0], c[1], c[2])
+		}()
+	}
+}
+
+func TestLegendre(t *testing.T) {
+	cases := []struct {
+		a, p int
+		want int
+	}{
+		{0, 3, 0},
+		{1, 3, 1},
+		{2, 3, -1},
+		{-1, 5, 1},
+		{-1, 7, -1},
+		{2, 7, 1},
+		{3, 7, -1},
+		{14, 7, 0},
+		{1001, 9907, -1},
+		{2, 1000003, -1},
+	}
+	for _, c := range cases {
+		if l := primes.Legendre(c.a, c.p); l != c.want {
+			t.Errorf("Legendre(%d,%d) == %d, want %d", c.a, c.p, l, c.want)
+		}
+	}
+
+	// Check against the squares modulo each prime p
+	for _, p := range primes.Sieve(500)[1:] {
+		isSquare := make([]bool, p)
+		for x := 0; x < p; x++ {
+			isSquare[x*x%p] = true
+		}
+		for a := -p; a < 2*p; a++ {
+			r := (a%p + p) % p
+			want := -1
+			if r == 0 {
+				want = 0
+			} else if isSquare[r] {
+				want = 1
+			}
+			if l := primes.Legendre(a, p); l != want {
+				t.Errorf("Legendre(%d,%d) == %d, want %d", a, p, l, want)
+			}
+		}
+	}
+
+	// Legendre must reject moduli that are not odd primes
+	for _, p := range []int{-3, 0, 1, 2, 9, 15, 561, 9973 * 9973} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Legendre(1,%d) did not panic", p)
+				}
+			}()
+			primes.Legendre(1, p)
+		}()
+	}
+}
+
+func TestJacobi(t *testing.T) {
+	cases := []struct {
+		a, n int
+		want int
+	}{
+		{0, 1, 1},
+		{5, 1, 1},
+		{0, 3, 0},
+		{2, 3, -1},
+		{-1, 7, -1},
+		{2, 15, 1},
+		{7, 15, -1},
+		{6, 15, 0},
+		{19, 45, 1},
+		{8, 21, -1},
+		{5, 21, 1},
+		{1001, 9907, -1},
+		{-1001, 9907, 1},
+		{2, 1000003, -1},
+		{1000003, 9973 * 9973, 1},
+	}
+	for _, c := range cases {
+		if j := primes.Jacobi(c.a, c.n); j != c.want {
+			t.Errorf("Jacobi(%d,%d) == %d, want %d", c.a, c.n, j, c.want)
+		}
+	}
+
+	// Jacobi must agree with Legendre for odd prime moduli and with the
+	// product of the Legendre symbols over the prime factors of n otherwise
+	for n := 1; n < 600; n += 2 {
+		fs := primes.FactorizeMap(n)
+		for a := -n; a < 2*n; a++ {
+			want := 1
+			for p, k := range fs {
+				for ; k > 0; k-- {
+					want *= primes.Legendre(a, p)
+				}
+			}
+			j := primes.Jacobi(a, n)
+			if j != want {
+				t.Errorf("Jacobi(%d,%d) == %d, want %d", a, n, j, want)
+			}
+			if primes.IsPrime(n) {
+				if l := primes.Legendre(a, n); j != l {
+					t.Errorf("Jacobi(%d,%d) == %d, but Legendre(%d,%d) == %d", a, n, j, a, n, l)
+				}
+			}
+		}
+	}
+
+	// Jacobi must reject moduli that are not odd and positive
+	for _, n := range []int{-3, -1, 0, 2, 10} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Jacobi(1,%d) did not panic", n)
+				}
+			}()
+			primes.Jacobi(1, n)
+		}()
+	}
+}
+
+func TestSmallestNonResidue(t *testing.T) {
+	cases := []struct {
+		p    int
+		want int
+		ok   bool
+	}{
+		{-3, 0, false},
+		{0, 0, false},
+		{1, 0, false},
+		{2, 0, false},
+		{3, 2, true},
+		{5, 2, true},
+		{7, 3, true},
+		{9, 0, false},
+		{23, 5, true},
+		{71, 7, true},
+		{311, 11, true},
+	}
+	for _, c := range cases {
+		if a, ok := primes.SmallestNonResidue(c.p); a != c.want || ok != c.ok {
+			t.Errorf("SmallestNonResidue(%d) == (%d,%v), want (%d,%v)", c.p, a, ok, c.want, c.ok)
+		}
+	}
+
+	// Check against the squares modulo each prime p
+	for _, p := range primes.Sieve(2000)[1:] {
+		isSquare := make([]bool, p)
+		for x := 0; x < p; x++ {
+			isSquare[x*x%p] = true
+		}
+		want := 1
+		for isSquare[want] {
+			want++
+		}
+		if a, ok := primes.SmallestNonResidue(p); a != want || !ok {
+			t.Errorf("SmallestNonResidue(%d) == (%d,%v), want (%d,true)", p, a, ok, want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+import "math"
+
+// NextPrimeInclusive returns the smallest prime number greater than or
+// equal to n. It tests the odd numbers from n upward with IsPrimeMR.
+// If there is no such prime that fits in an int, it returns 0.
+func NextPrimeInclusive(n int) int {
+	if n <= 2 {
+		return 2
+	}
+	if n%2 == 0 {
+		n++
+	}
+	for ; ; n += 2 {
+		if IsPrimeMR(int64(n)) {
+			return n
+		}
+		if n > math.MaxInt-2 {
+			return 0
+		}
+	}
+}
+
+// PrimeAfterShift returns the smallest prime number greater than or equal
+// to n*10^zeros, that is the first prime found after shifting the decimal
+// digits of n to the left by the given number of zeros; for example,
+// PrimeAfterShift(1,6) == 1000003.
+// It is handy for picking primes close to round numbers.
+// If zeros is negative, or if either n*10^zeros or the prime following it
+// does not fit in an int, it returns 0.
+func PrimeAfterShift(n, zeros int) int {
+	if zeros < 0 {
+		return 0
+	}
+	for ; zeros > 0; zeros-- {
+		if n > math.MaxInt/10 || n < math.MinInt/10 {
+			return 0
+		}
+		n *= 10
+	}
+	return NextPrimeInclusive(n)
+}
@@ -0,0 +1,88 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestNextPrimeInclusive(t *testing.T) {
+	cases := []struct {
+		n    int
+		want int
+	}{
+		{math.MinInt, 2},
+		{-1, 2},
+		{0, 2},
+		{2, 2},
+		{3, 3},
+		{4, 5},
+		{90, 97},
+		{9974, 10007},
+		{1000000, 1000003},
+		{1000004, 1000033},
+		{math.MaxInt32, math.MaxInt32},
+	}
+	for _, c := range cases {
+		if p := primes.NextPrimeInclusive(c.n); p != c.want {
+			t.Errorf("NextPrimeInclusive(%d) == %d, want %d", c.n, p, c.want)
+		}
+	}
+
+	// Check against the primes generated by Sieve
+	ps := primes.Sieve(100000)
+	i := 0
+	for n := 0; n <= ps[len(ps)-1]; n++ {
+		if ps[i] < n {
+			i++
+		}
+		if p := primes.NextPrimeInclusive(n); p != ps[i] {
+			t.Errorf("NextPrimeInclusive(%d) == %d, want %d", n, p, ps[i])
+		}
+	}
+}
+
+func TestPrimeAfterShift(t *testing.T) {
+	cases := []struct {
+		n, zeros int
+		want     int
+	}{
+		{1, 0, 2},
+		{1, 1, 11},
+		{1, 2, 101},
+		{1, 6, 1000003},
+		{9, 3, 9001},
+		{12, 2, 1201},
+		{1, -1, 0},
+		{math.MaxInt / 10, 2, 0},
+		{math.MaxInt, 1, 0},
+	}
+	for _, c := range cases {
+		if p := primes.PrimeAfterShift(c.n, c.zeros); p != c.want {
+			t.Errorf("PrimeAfterShift(%d,%d) == %d, want %d", c.n, c.zeros, p, c.want)
+		}
+	}
+}
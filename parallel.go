@@ -0,0 +1,130 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+import (
+	"runtime"
+	"sync"
+)
+
+// SieveParallel returns a list of the prime numbers less than or equal to n,
+// exactly like Sieve, but it spreads the work over several goroutines.
+// It first computes the primes up to sqrt(n); then it splits the rest of the
+// range into chunks that are handed out to the given number of workers, each
+// running a segmented sieve of Eratosthenes over its chunks; finally, it
+// concatenates the primes found in each chunk in order.
+// If n is less than 2, it returns an empty list.
+// If workers is less than 1, it uses one worker per available CPU
+// (see runtime.GOMAXPROCS).
+func SieveParallel(n, workers int) []int {
+	if n < 4 {
+		// Too small to split; this also handles n < 2, which has no primes
+		return Sieve(n)
+	}
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	sqrtn := isqrt(n)
+	base := Sieve(sqrtn)
+	if sqrtn >= n {
+		return base
+	}
+	ps := []int64{}
+	for _, p := range base {
+		if p > 2 {
+			ps = append(ps, int64(p))
+		}
+	}
+
+	// Split (sqrtn,n] into a few chunks per worker so that the load stays
+	// balanced even if some chunks take longer than others; no chunk is
+	// narrower than minChunk, so there is no point in having more workers
+	// than chunks of that width
+	const minChunk = 2 * segmentSize
+	if m := (n-sqrtn)/minChunk + 1; workers > m {
+		workers = m
+	}
+	nchunks := 4 * workers
+	width := (n - sqrtn + nchunks - 1) / nchunks
+	if width < minChunk {
+		width = minChunk
+	}
+	type chunk struct {
+		lo, hi int
+	}
+	var chunks []chunk
+	for lo := sqrtn + 1; lo <= n; lo += width {
+		hi := n
+		if n-lo >= width {
+			hi = lo + width - 1
+		}
+		chunks = append(chunks, chunk{lo, hi})
+		if hi == n {
+			break
+		}
+	}
+
+	results := make([][]int, len(chunks))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	if workers > len(chunks) {
+		workers = len(chunks)
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				c := chunks[i]
+				lo := c.lo
+				if lo%2 == 0 {
+					lo++
+				}
+				var rs []int
+				sieveSegmentsWith(ps, int64(lo), int64(c.hi), func(b int64, a []bool) bool {
+					for j, composite := range a {
+						if !composite {
+							rs = append(rs, int(b)+2*j)
+						}
+					}
+					return true
+				})
+				results[i] = rs
+			}
+		}()
+	}
+	for i := range chunks {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	// Merge the results in order
+	pi, _ := Pi(n)
+	all := make([]int, 0, pi)
+	all = append(all, base...)
+	for _, rs := range results {
+		all = append(all, rs...)
+	}
+	return all
+}
@@ -0,0 +1,138 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+// IsPerfect returns true if n is a perfect number, that is a positive
+// integer equal to the sum of its proper divisors (e.g. 6 == 1+2+3).
+// Equivalently, n is perfect if DivisorSum(n) == 2*n.
+// See https://en.wikipedia.org/wiki/Perfect_number for details.
+func IsPerfect(n int) bool {
+	return n > 0 && DivisorSum(n) == 2*n
+}
+
+// PerfectNumbers returns a list of the perfect numbers less than or equal
+// to limit.
+// It tests each number in turn with IsPerfect, so it is only practical for
+// relatively small values of limit.
+func PerfectNumbers(limit int) []int {
+	ns := []int{}
+	for n := 1; n <= limit; n++ {
+		if IsPerfect(n) {
+			ns = append(ns, n)
+		}
+	}
+	return ns
+}
+
+// AliquotSum returns the aliquot sum s(n) of n, that is the sum of the
+// proper divisors of n (all its positive divisors but n itself); for
+// example, AliquotSum(12) == 1+2+3+4+6 == 16.
+// It is computed as DivisorSum(n)-n, so that n is perfect if and only if
+// AliquotSum(n) == n, and AliquotSum(p) == 1 for any prime p.
+// If n is less than 1, it returns 0.
+// See https://en.wikipedia.org/wiki/Aliquot_sum for details.
+func AliquotSum(n int) int {
+	if n < 1 {
+		return 0
+	}
+	return DivisorSum(n) - n
+}
+
+// AliquotSequence returns the aliquot sequence starting at n, that is n,
+// s(n), s(s(n)), ... where s is AliquotSum; for example,
+// AliquotSequence(12,10) returns [12 16 15 9 4 3].
+// The sequence ends as soon as it reaches 0, a prime (whose aliquot sum is
+// 1), or a perfect number (which is a fixed point of s), or once it has
+// taken maxSteps steps, so it has at most maxSteps+1 terms. Amicable pairs
+// and longer sociable cycles repeat until maxSteps is reached: for
+// example, AliquotSequence(220,3) returns [220 284 220 284].
+// Whether every aliquot sequence eventually ends or becomes periodic is an
+// open problem (the Catalan-Dickson conjecture); some, like the one
+// starting at 276, grow large enough that DivisorSum may overflow, which
+// is not detected.
+// If n is less than 1, it returns an empty list.
+// See https://en.wikipedia.org/wiki/Aliquot_sequence for details.
+func AliquotSequence(n, maxSteps int) []int {
+	seq := []int{}
+	if n < 1 {
+		return seq
+	}
+	seq = append(seq, n)
+	for i := 0; i < maxSteps && n != 0 && !IsPrime(n) && !IsPerfect(n); i++ {
+		n = AliquotSum(n)
+		seq = append(seq, n)
+	}
+	return seq
+}
+
+// AmicablePairs returns the amicable pairs (a,b) with a < b <= limit, in
+// increasing order of a; two distinct numbers are amicable if each one is
+// the aliquot sum of the other, like 220 and 284 (see AliquotSum).
+// For each a up to limit, it computes b == AliquotSum(a) and keeps the
+// pair if b is in (a,limit] and AliquotSum(b) == a, so it is only
+// practical for relatively small values of limit, like PerfectNumbers.
+// See https://en.wikipedia.org/wiki/Amicable_numbers for details.
+func AmicablePairs(limit int) [][2]int {
+	ps := [][2]int{}
+	for a := 2; a < limit; a++ {
+		if b := AliquotSum(a); b > a && b <= limit && AliquotSum(b) == a {
+			ps = append(ps, [2]int{a, b})
+		}
+	}
+	return ps
+}
+
+// IsAbundant returns true if n is an abundant number, that is a positive
+// integer smaller than the sum of its proper divisors (e.g. 12 < 1+2+3+4+6);
+// equivalently, n is abundant if DivisorSum(n) > 2*n.
+// The smallest abundant number is 12 and the smallest odd one is 945.
+// See https://en.wikipedia.org/wiki/Abundant_number for details.
+func IsAbundant(n int) bool {
+	return n > 0 && AliquotSum(n) > n
+}
+
+// IsDeficient returns true if n is a deficient number, that is a positive
+// integer greater than the sum of its proper divisors (e.g. 10 > 1+2+5);
+// equivalently, n is deficient if DivisorSum(n) < 2*n.
+// Every positive integer is either deficient, perfect, or abundant; all the
+// primes and prime powers, as well as 1, are deficient.
+// See https://en.wikipedia.org/wiki/Deficient_number for details.
+func IsDeficient(n int) bool {
+	return n > 0 && AliquotSum(n) < n
+}
+
+// AbundantNumbers returns a list of the abundant numbers less than or
+// equal to limit; for example, AbundantNumbers(40) returns [12 18 20 24 30
+// 36 40].
+// Like PerfectNumbers, it tests each number in turn with IsAbundant, so it
+// is only practical for relatively small values of limit.
+// See https://oeis.org/A005101 for details.
+func AbundantNumbers(limit int) []int {
+	ns := []int{}
+	for n := 1; n <= limit; n++ {
+		if IsAbundant(n) {
+			ns = append(ns, n)
+		}
+	}
+	return ns
+}
@@ -0,0 +1,266 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestIsPerfect(t *testing.T) {
+	cases := []struct {
+		n    int
+		want bool
+	}{
+		{-6, false},
+		{0, false},
+		{1, false},
+		{2, false},
+		{6, true},
+		{12, false},
+		{28, true},
+		{496, true},
+		{8128, true},
+		{33550336, true},
+		{33550337, false},
+	}
+	for _, c := range cases {
+		if p := primes.IsPerfect(c.n); p != c.want {
+			t.Errorf("IsPerfect(%d) == %v, want %v", c.n, p, c.want)
+		}
+	}
+}
+
+func TestPerfectNumbers(t *testing.T) {
+	cases := []struct {
+		limit int
+		want  []int
+	}{
+		{-1, []int{}},
+		{0, []int{}},
+		{5, []int{}},
+		{6, []int{6}},
+		{100, []int{6, 28}},
+		{10000, []int{6, 28, 496, 8128}},
+	}
+	for _, c := range cases {
+		ns := primes.PerfectNumbers(c.limit)
+		if !reflect.DeepEqual(ns, c.want) {
+			t.Errorf("PerfectNumbers(%d) == %v, want %v", c.limit, ns, c.want)
+		}
+	}
+
+	// There are no perfect numbers between 8128 and 33550336
+	for n := 8129; n < 1000000; n++ {
+		if primes.IsPerfect(n) {
+			t.Errorf("IsPerfect(%d) == true, want false", n)
+		}
+	}
+}
+
+func TestAliquotSum(t *testing.T) {
+	cases := []struct {
+		n    int
+		want int
+	}{
+		{-12, 0},
+		{0, 0},
+		{1, 0},
+		{2, 1},
+		{6, 6},
+		{12, 16},
+		{28, 28},
+		{220, 284},
+		{284, 220},
+		{9973, 1},
+		{1 << 20, 1<<20 - 1},
+	}
+	for _, c := range cases {
+		if s := primes.AliquotSum(c.n); s != c.want {
+			t.Errorf("AliquotSum(%d) == %d, want %d", c.n, s, c.want)
+		}
+	}
+
+	// Check against the sum of the proper divisors
+	for n := 1; n < 2000; n++ {
+		want := 0
+		for d := 1; d < n; d++ {
+			if n%d == 0 {
+				want += d
+			}
+		}
+		if s := primes.AliquotSum(n); s != want {
+			t.Errorf("AliquotSum(%d) == %d, want %d", n, s, want)
+		}
+	}
+}
+
+func TestAliquotSequence(t *testing.T) {
+	cases := []struct {
+		n, maxSteps int
+		want        []int
+	}{
+		{-10, 10, []int{}},
+		{0, 10, []int{}},
+		{1, 10, []int{1, 0}},
+		{7, 10, []int{7}},
+		{10, 10, []int{10, 8, 7}},
+		{12, 10, []int{12, 16, 15, 9, 4, 3}},
+		{12, 2, []int{12, 16, 15}},
+		{12, 0, []int{12}},
+		{12, -1, []int{12}},
+		{95, 10, []int{95, 25, 6}},
+		// A perfect number is a fixed point
+		{28, 10, []int{28}},
+		{8128, 10, []int{8128}},
+		// An amicable pair is a cycle of length 2
+		{220, 3, []int{220, 284, 220, 284}},
+		{284, 4, []int{284, 220, 284, 220, 284}},
+		// The first sociable cycle, of length 5
+		{12496, 5, []int{12496, 14288, 15472, 14536, 14264, 12496}},
+	}
+	for _, c := range cases {
+		seq := primes.AliquotSequence(c.n, c.maxSteps)
+		if !reflect.DeepEqual(seq, c.want) {
+			t.Errorf("AliquotSequence(%d,%d) == %v, want %v", c.n, c.maxSteps, seq, c.want)
+		}
+	}
+}
+
+func TestAmicablePairs(t *testing.T) {
+	cases := []struct {
+		limit int
+		want  [][2]int
+	}{
+		{-1, [][2]int{}},
+		{0, [][2]int{}},
+		{283, [][2]int{}},
+		{284, [][2]int{{220, 284}}},
+		{1209, [][2]int{{220, 284}}},
+		{1210, [][2]int{{220, 284}, {1184, 1210}}},
+		// See https://oeis.org/A063990
+		{10000, [][2]int{{220, 284}, {1184, 1210}, {2620, 2924}, {5020, 5564}, {6232, 6368}}},
+	}
+	for _, c := range cases {
+		ps := primes.AmicablePairs(c.limit)
+		if !reflect.DeepEqual(ps, c.want) {
+			t.Errorf("AmicablePairs(%d) == %v, want %v", c.limit, ps, c.want)
+		}
+	}
+
+	// See https://projecteuler.net/problem=21
+	sum := 0
+	for _, p := range primes.AmicablePairs(9999) {
+		sum += p[0] + p[1]
+	}
+	if sum != 31626 {
+		t.Errorf("the amicable numbers below 10000 add up to %d, want 31626", sum)
+	}
+
+	// Check that each pair is amicable
+	for _, p := range primes.AmicablePairs(100000) {
+		if primes.AliquotSum(p[0]) != p[1] || primes.AliquotSum(p[1]) != p[0] {
+			t.Errorf("AmicablePairs(100000) contains %v", p)
+		}
+	}
+}
+
+func TestIsAbundantAndIsDeficient(t *testing.T) {
+	cases := []struct {
+		n                   int
+		abundant, deficient bool
+	}{
+		{-12, false, false},
+		{0, false, false},
+		{1, false, true},
+		{2, false, true},
+		{6, false, false},
+		{10, false, true},
+		{11, false, true},
+		{12, true, false},
+		{28, false, false},
+		{945, true, false},
+		{1 << 20, false, true},
+	}
+	for _, c := range cases {
+		if a := primes.IsAbundant(c.n); a != c.abundant {
+			t.Errorf("IsAbundant(%d) == %v, want %v", c.n, a, c.abundant)
+		}
+		if d := primes.IsDeficient(c.n); d != c.deficient {
+			t.Errorf("IsDeficient(%d) == %v, want %v", c.n, d, c.deficient)
+		}
+	}
+
+	// All the primes are deficient
+	for _, p := range primes.Sieve(100000) {
+		if !primes.IsDeficient(p) || primes.IsAbundant(p) {
+			t.Errorf("IsDeficient(%d) == false, want true", p)
+		}
+	}
+
+	// Every positive integer is either deficient, perfect, or abundant
+	for n := 1; n < 100000; n++ {
+		k := 0
+		for _, b := range []bool{primes.IsDeficient(n), primes.IsPerfect(n), primes.IsAbundant(n)} {
+			if b {
+				k++
+			}
+		}
+		if k != 1 {
+			t.Errorf("%d is in %d of the classes deficient, perfect, and abundant, want 1", n, k)
+		}
+	}
+}
+
+func TestAbundantNumbers(t *testing.T) {
+	cases := []struct {
+		limit int
+		want  []int
+	}{
+		{-1, []int{}},
+		{0, []int{}},
+		{11, []int{}},
+		{12, []int{12}},
+		// See https://oeis.org/A005101
+		{100, []int{12, 18, 20, 24, 30, 36, 40, 42, 48, 54, 56, 60, 66, 70, 72, 78, 80, 84, 88, 90, 96, 100}},
+	}
+	for _, c := range cases {
+		ns := primes.AbundantNumbers(c.limit)
+		if !reflect.DeepEqual(ns, c.want) {
+			t.Errorf("AbundantNumbers(%d) == %v, want %v", c.limit, ns, c.want)
+		}
+	}
+
+	// 945 is the smallest odd abundant number
+	ns := primes.AbundantNumbers(945)
+	for _, n := range ns[:len(ns)-1] {
+		if n%2 == 1 {
+			t.Errorf("AbundantNumbers(945) contains %d", n)
+		}
+	}
+	if last := ns[len(ns)-1]; last != 945 {
+		t.Errorf("the largest abundant number <= 945 is %d, want 945", last)
+	}
+}
@@ -0,0 +1,156 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+// rhoSmallPrimes is the number of cached primes (those below 100) tried by
+// trial division before PollardRho turns to Brent's cycle search
+const rhoSmallPrimes = 25
+
+// rhoBatch is the number of steps of Brent's cycle search whose
+// differences are multiplied together before taking a GCD with n
+const rhoBatch = 128
+
+// PollardRho returns a nontrivial factor of n if n is composite, or n
+// itself if n is prime (as decided by IsPrimeMR).
+// Factors below 100 are found by trial division; for any other n, it uses
+// Pollard's rho algorithm with Brent's cycle detection, which iterates the
+// map x -> x^2+c modulo n until the differences of the values it visits
+// share a factor with n. A prime factor p shows up after about sqrt(p)
+// steps, so PollardRho can split a product of two primes near 10^9 in a
+// few tens of thousands of steps, where trial division would need about
+// 10^8.
+// The factor returned is not necessarily prime, nor the smallest one.
+// If n is less than 2, it returns n.
+// See https://en.wikipedia.org/wiki/Pollard%27s_rho_algorithm for details.
+func PollardRho(n int64) int64 {
+	if n < 2 || IsPrimeMR(n) {
+		return n
+	}
+	primes, _ := cachedPrimes()
+	for _, p := range primes[:rhoSmallPrimes] {
+		if n%int64(p) == 0 {
+			return int64(p)
+		}
+	}
+	// Try increasing values of c until the search finds a proper factor
+	for c := uint64(1); ; c++ {
+		if d := brentRho(uint64(n), c); d != uint64(n) {
+			return int64(d)
+		}
+	}
+}
+
+// brentRho runs Brent's variant of Pollard's rho algorithm on the odd
+// composite n with the map x -> x^2+c modulo n.
+// It returns a nontrivial factor of n, or n if the search failed.
+// See R. P. Brent, "An improved Monte Carlo factorization algorithm",
+// BIT 20 (1980), pp. 176-184.
+func brentRho(n, c uint64) uint64 {
+	f := func(x uint64) uint64 {
+		// x^2 mod n < n and c < n fit in a uint64 together since n < 2^63
+		return (mulMod64(x, x, n) + c) % n
+	}
+	diff := func(x, y uint64) uint64 {
+		if x > y {
+			return x - y
+		}
+		return y - x
+	}
+	x, y, ys := uint64(0), uint64(2), uint64(0)
+	q, g := uint64(1), uint64(1)
+	for r := 1; g == 1; r *= 2 {
+		x = y
+		for i := 0; i < r; i++ {
+			y = f(y)
+		}
+		// Take r more steps, checking the GCD once every rhoBatch steps
+		for k := 0; k < r && g == 1; k += rhoBatch {
+			ys = y
+			for i := 0; i < rhoBatch && i < r-k; i++ {
+				y = f(y)
+				q = mulMod64(q, diff(x, y), n)
+			}
+			g = gcd64(q, n)
+		}
+	}
+	if g == n {
+		// The batch overshot the factor (or q hit 0); retrace its steps
+		// one at a time
+		for g = 1; g == 1; {
+			ys = f(ys)
+			g = gcd64(diff(x, ys), n)
+		}
+	}
+	return g
+}
+
+// gcd64 returns the greatest common divisor of a and b.
+func gcd64(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// FactorizeLarge returns a map from each prime factor of n to its
+// multiplicity, like FactorizeMap, but for any int64 n; for example,
+// FactorizeLarge(999999937*1000000007) returns
+// map[999999937:1 1000000007:1].
+// It divides out the cached primes by trial division and splits whatever
+// is left with PollardRho, recursively, until every factor passes
+// IsPrimeMR, so its running time depends on the second largest prime
+// factor p of n as about sqrt(p) rather than p.
+// If n is less than 2, it returns an empty map.
+func FactorizeLarge(n int64) map[int64]int {
+	fs := make(map[int64]int)
+	if n < 2 {
+		return fs
+	}
+	primes, _ := cachedPrimes()
+	for _, p := range primes {
+		q := int64(p)
+		if q > n/q {
+			break
+		}
+		for n%q == 0 {
+			n /= q
+			fs[q]++
+		}
+	}
+	var split func(m int64)
+	split = func(m int64) {
+		if m == 1 {
+			return
+		}
+		d := PollardRho(m)
+		if d == m {
+			// m is prime
+			fs[m]++
+			return
+		}
+		split(d)
+		split(m / d)
+	}
+	split(n)
+	return fs
+}
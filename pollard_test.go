@@ -0,0 +1,119 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestPollardRho(t *testing.T) {
+	// Primes and numbers less than 2 are returned as they are
+	for _, n := range []int64{-5, 0, 1, 2, 3, 9973, 1000000007, math.MaxInt32, 9223372036854775783} {
+		if d := primes.PollardRho(n); d != n {
+			t.Errorf("PollardRho(%d) == %d, want %d", n, d, n)
+		}
+	}
+
+	// Composites are split into two nontrivial factors
+	ns := []int64{
+		4, 9, 91, 561, 10403, 9973 * 9973,
+		999999937 * 1000000007,
+		1000000007 * 1000000009,
+		999999937 * 999999937,
+		3 * 1000000007 * 1000000009,
+		4611686014132420609, // (2^31-1)^2
+		1 << 62,
+	}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		ns = append(ns, 2+rng.Int63n(1<<40))
+	}
+	for _, n := range ns {
+		if primes.IsPrimeMR(n) {
+			continue
+		}
+		if d := primes.PollardRho(n); d <= 1 || d >= n || n%d != 0 {
+			t.Errorf("PollardRho(%d) == %d, not a nontrivial factor", n, d)
+		}
+	}
+}
+
+func TestFactorizeLarge(t *testing.T) {
+	cases := []struct {
+		n    int64
+		want map[int64]int
+	}{
+		{-1, map[int64]int{}},
+		{0, map[int64]int{}},
+		{1, map[int64]int{}},
+		{2, map[int64]int{2: 1}},
+		{360, map[int64]int{2: 3, 3: 2, 5: 1}},
+		{999999937 * 1000000007, map[int64]int{999999937: 1, 1000000007: 1}},
+		{1000000007 * 1000000009, map[int64]int{1000000007: 1, 1000000009: 1}},
+		{999999937 * 999999937, map[int64]int{999999937: 2}},
+		{2 * 3 * 1000000007 * 1000000009, map[int64]int{2: 1, 3: 1, 1000000007: 1, 1000000009: 1}},
+		{10007 * 10009 * 10037 * 10039, map[int64]int{10007: 1, 10009: 1, 10037: 1, 10039: 1}},
+		{1 << 62, map[int64]int{2: 62}},
+		{math.MaxInt64, map[int64]int{7: 2, 73: 1, 127: 1, 337: 1, 92737: 1, 649657: 1}},
+		{9223372036854775783, map[int64]int{9223372036854775783: 1}},
+	}
+	for _, c := range cases {
+		if fs := primes.FactorizeLarge(c.n); !reflect.DeepEqual(fs, c.want) {
+			t.Errorf("FactorizeLarge(%d) == %v, want %v", c.n, fs, c.want)
+		}
+	}
+
+	// Check against FactorizeMap
+	for n := 1; n <= 100000; n += 7 {
+		want := map[int64]int{}
+		for p, k := range primes.FactorizeMap(n) {
+			want[int64(p)] = k
+		}
+		if fs := primes.FactorizeLarge(int64(n)); !reflect.DeepEqual(fs, want) {
+			t.Errorf("FactorizeLarge(%d) == %v, want %v", n, fs, want)
+		}
+	}
+
+	// The product of the factors must give back n
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		n := 2 + rng.Int63n(math.MaxInt64-2)
+		m := int64(1)
+		for p, k := range primes.FactorizeLarge(n) {
+			if !primes.IsPrimeMR(p) {
+				t.Errorf("FactorizeLarge(%d) lists %d, which is not prime", n, p)
+			}
+			for ; k > 0; k-- {
+				m *= p
+			}
+		}
+		if m != n {
+			t.Errorf("FactorizeLarge(%d) multiplies to %d", n, m)
+		}
+	}
+}
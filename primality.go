@@ -0,0 +1,203 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+import (
+	"math"
+	"math/bits"
+	"math/rand"
+	"sort"
+)
+
+// mrWitnesses is a set of bases for which the Miller-Rabin test is known
+// to be deterministic for all n < 3.3*10^24, a bound well above math.MaxInt64.
+var mrWitnesses = []uint64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37}
+
+// IsPrimeMR is a primality test: it returns true if n is prime.
+// It uses a deterministic version of the Miller-Rabin test which checks
+// whether n is a strong probable prime to each of the bases
+// 2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, and 37; this set of bases is
+// known to produce no false positives for any n < 3.3*10^24.
+// Unlike IsPrime, the running time of IsPrimeMR grows with log(n) only,
+// so it remains fast even for n close to math.MaxInt64.
+// Small values of n are looked up in the cached table of primes.
+// See https://en.wikipedia.org/wiki/Miller%E2%80%93Rabin_primality_test
+// for details.
+func IsPrimeMR(n int64) bool {
+	switch {
+	case n < 2:
+		return false
+	case n%2 == 0:
+		return n == 2
+	}
+	primes, _ := cachedPrimes()
+	if pMax := primes[len(primes)-1]; n <= int64(pMax) {
+		// If n is prime, it must be in the cache
+		i := sort.SearchInts(primes, int(n))
+		return int(n) == primes[i]
+	}
+	// Write n-1 as d*2^s with d odd
+	m := uint64(n)
+	s := bits.TrailingZeros64(m - 1)
+	d := (m - 1) >> uint(s)
+	for _, a := range mrWitnesses {
+		if !strongProbablePrime(m, a, d, s) {
+			return false
+		}
+	}
+	return true
+}
+
+// FermatTest is a probabilistic primality test: it returns false if n is
+// certainly composite and true if n is probably prime.
+// For each witness a, it checks whether a^(n-1) is congruent to 1 modulo n
+// (computed with ModPow), as Fermat's little theorem requires of any prime
+// n that does not divide a; witnesses that are multiples of n are skipped,
+// and any witness sharing a factor with n proves it composite.
+// The test is cheap, so it makes a good pre-filter before an exact test,
+// but it can be fooled: a composite n that passes it for witness a is a
+// Fermat pseudoprime to base a (e.g. 341 == 11*31 to base 2) and the
+// Carmichael numbers (e.g. 561 == 3*11*17) pass it for every witness
+// coprime to them; the strong test used by IsPrimeMR has no such blind
+// spot.
+// If n is less than 2, it returns false; if witnesses is empty, it
+// returns true for any n >= 2.
+// See https://en.wikipedia.org/wiki/Fermat_primality_test for details.
+func FermatTest(n int, witnesses []int) bool {
+	if n < 2 {
+		return false
+	}
+	for _, a := range witnesses {
+		if a%n != 0 && ModPow(a, n-1, n) != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// SolovayStrassen is a probabilistic primality test: it returns false if n
+// is certainly composite and true if n is probably prime.
+// Each of the given number of rounds picks a random base a in [2,n-1] with
+// rng and checks Euler's criterion, that a^((n-1)/2) is congruent to the
+// Jacobi symbol (a/n) modulo n, as it is for any odd prime n.
+// Unlike FermatTest, it has no blind spot like the Carmichael numbers: for
+// any odd composite n, at least half the bases violate the criterion, so
+// a composite passes each round with probability at most 1/2 and all of
+// them with probability at most 1/2^rounds.
+// The strong test used by IsPrimeMR is at least as good for each base and
+// bounds the error by 1/4 per round instead.
+// If n is less than 2, it returns false; if rounds is not positive, it
+// returns true for 2 and every odd n >= 3.
+// See https://en.wikipedia.org/wiki/Solovay%E2%80%93Strassen_primality_test
+// for details.
+func SolovayStrassen(n int, rounds int, rng *rand.Rand) bool {
+	switch {
+	case n < 2:
+		return false
+	case n%2 == 0:
+		return n == 2
+	}
+	for i := 0; i < rounds; i++ {
+		a := 2 + int(rng.Int63n(int64(n-2)))
+		j := Jacobi(a, n)
+		if j == 0 {
+			// a and n have a common factor
+			return false
+		}
+		x := ModPow(a, (n-1)/2, n)
+		if (j == 1 && x != 1) || (j == -1 && x != n-1) {
+			return false
+		}
+	}
+	return true
+}
+
+// CarmichaelNumbers returns the Carmichael numbers less than or equal to
+// limit in increasing order; for example, CarmichaelNumbers(2000) returns
+// [561 1105 1729].
+// The Carmichael numbers are the composite numbers that pass FermatTest for
+// every witness coprime to them. They are identified by Korselt's
+// criterion: a composite n is a Carmichael number if and only if it is
+// squarefree and p-1 divides n-1 for every prime factor p of n.
+// The numbers up to limit are factored together with a segmented sieve.
+// See https://en.wikipedia.org/wiki/Carmichael_number for details.
+func CarmichaelNumbers(limit int) []int {
+	cs := []int{}
+	if limit == math.MaxInt {
+		limit--
+	}
+	factorizeRange(2, limit+1, func(n int, fs []primePower) bool {
+		// Carmichael numbers are odd and have at least three prime factors
+		if n%2 == 0 || len(fs) < 3 {
+			return true
+		}
+		for _, f := range fs {
+			if f.k > 1 || (n-1)%(f.p-1) != 0 {
+				return true
+			}
+		}
+		cs = append(cs, n)
+		return true
+	})
+	return cs
+}
+
+// strongProbablePrime returns true if the odd number n > a is a strong
+// probable prime to base a, where n-1 == d*2^s with d odd.
+func strongProbablePrime(n, a, d uint64, s int) bool {
+	x := powMod64(a, d, n)
+	if x == 1 || x == n-1 {
+		return true
+	}
+	for r := 1; r < s; r++ {
+		x = mulMod64(x, x, n)
+		switch x {
+		case n - 1:
+			return true
+		case 1:
+			// x has a non-trivial square root of 1 modulo n
+			return false
+		}
+	}
+	return false
+}
+
+// mulMod64 returns (a*b) mod m without overflowing.
+func mulMod64(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return bits.Rem64(hi, lo, m)
+}
+
+// powMod64 returns (a^e) mod m computed by binary exponentiation.
+func powMod64(a, e, m uint64) uint64 {
+	r := uint64(1) % m
+	a %= m
+	for e > 0 {
+		if e&1 == 1 {
+			r = mulMod64(r, a, m)
+		}
+		a = mulMod64(a, a, m)
+		e >>= 1
+	}
+	return r
+}
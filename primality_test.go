@@ -0,0 +1,237 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestIsPrimeMR(t *testing.T) {
+	// Check a few simple cases, including large primes and strong
+	// pseudoprimes to several of the smallest prime bases
+	cases := []struct {
+		n    int64
+		want bool
+	}{
+		{math.MinInt64, false},
+		{-1, false},
+		{0, false},
+		{1, false},
+		{2, true},
+		{3, true},
+		{6, false},
+		{561, false},
+		{3215031751, false},
+		{1000003, true},
+		{2147483647, true},
+		{3825123056546413051, false},
+		{9223372036854775783, true},
+		{math.MaxInt64, false},
+	}
+	for _, c := range cases {
+		p := primes.IsPrimeMR(c.n)
+		if p != c.want {
+			t.Errorf("IsPrimeMR(%d) == %v, want %v", c.n, p, c.want)
+		}
+	}
+
+	// Check against each continguous sequence of primes that the primes
+	// are classified as primes and the numbers in between as not.
+	for _, ps := range contiguousPrimes {
+		for _, p := range ps {
+			if !primes.IsPrimeMR(int64(p)) {
+				t.Errorf("IsPrimeMR(%d) == false, want true", p)
+			}
+		}
+		for i := 1; i < len(ps); i++ {
+			for n := ps[i-1] + 1; n < ps[i]; n++ {
+				if primes.IsPrimeMR(int64(n)) {
+					t.Errorf("IsPrimeMR(%d) == true, want false", n)
+				}
+			}
+		}
+	}
+}
+
+func TestIsPrimeMRAgainstIsPrime(t *testing.T) {
+	for n := -1; n < 100000; n++ {
+		p := primes.IsPrimeMR(int64(n))
+		q := primes.IsPrime(n)
+		if p != q {
+			t.Errorf("IsPrimeMR(%d) == %v, want %v", n, p, q)
+		}
+	}
+	for i := 0; i < 10000; i++ {
+		n := rand.Intn(math.MaxInt32)
+		p := primes.IsPrimeMR(int64(n))
+		q := primes.IsPrime(n)
+		if p != q {
+			t.Errorf("IsPrimeMR(%d) == %v, want %v", n, p, q)
+		}
+	}
+}
+
+func TestFermatTest(t *testing.T) {
+	bases := []int{2, 3, 5, 7}
+	cases := []struct {
+		n         int
+		witnesses []int
+		want      bool
+	}{
+		{-7, bases, false},
+		{0, bases, false},
+		{1, bases, false},
+		{2, bases, true},
+		{3, bases, true},
+		{4, bases, false},
+		{9, nil, true},
+		{9973, bases, true},
+		{1000003, bases, true},
+		// Pseudoprimes to base 2 are caught by other witnesses
+		{341, []int{2}, true},
+		{341, bases, false},
+		// Carmichael numbers pass for every witness coprime to them...
+		{561, []int{2, 5, 7, 13, 101}, true},
+		{1105, []int{2, 3, 7, 11}, true},
+		// ...but not for a witness that shares a factor with them
+		{561, []int{3}, false},
+	}
+	for _, c := range cases {
+		if p := primes.FermatTest(c.n, c.witnesses); p != c.want {
+			t.Errorf("FermatTest(%d,%v) == %v, want %v", c.n, c.witnesses, p, c.want)
+		}
+	}
+
+	// Primes always pass and the base-2 test misses only the Fermat
+	// pseudoprimes to base 2 (see https://oeis.org/A001567)
+	pseudoprimes := []int{
+		341, 561, 645, 1105, 1387, 1729, 1905, 2047, 2465, 2701, 2821,
+		3277, 4033, 4369, 4371, 4681, 5461, 6601, 7957, 8321, 8481, 8911,
+	}
+	for n := 2; n <= 10000; n++ {
+		want := primes.IsPrime(n)
+		if len(pseudoprimes) > 0 && n == pseudoprimes[0] {
+			want = true
+			pseudoprimes = pseudoprimes[1:]
+		}
+		if p := primes.FermatTest(n, []int{2}); p != want {
+			t.Errorf("FermatTest(%d,[2]) == %v, want %v", n, p, want)
+		}
+	}
+}
+
+func TestSolovayStrassen(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	cases := []struct {
+		n      int
+		rounds int
+		want   bool
+	}{
+		{-7, 20, false},
+		{0, 20, false},
+		{1, 20, false},
+		{2, 20, true},
+		{3, 20, true},
+		{4, 0, false},
+		{9, 0, true},
+		{9, 20, false},
+		{561, 20, false},
+		{1105, 20, false},
+		{1729, 20, false},
+		{9973, 20, true},
+		{1000003, 20, true},
+		{9973 * 10007, 20, false},
+		{math.MaxInt32, 20, true},
+		{46337 * 46337, 20, false},
+	}
+	for _, c := range cases {
+		if p := primes.SolovayStrassen(c.n, c.rounds, rng); p != c.want {
+			t.Errorf("SolovayStrassen(%d,%d) == %v, want %v", c.n, c.rounds, p, c.want)
+		}
+	}
+
+	// Primes always pass, however many rounds, and the odd composites fail
+	// with overwhelming probability
+	for n := 3; n <= 20000; n += 2 {
+		want := primes.IsPrime(n)
+		if p := primes.SolovayStrassen(n, 30, rng); p != want {
+			t.Errorf("SolovayStrassen(%d,30) == %v, want %v", n, p, want)
+		}
+	}
+	for _, n := range primes.CarmichaelNumbers(100000) {
+		if primes.SolovayStrassen(n, 30, rng) {
+			t.Errorf("SolovayStrassen(%d,30) == true, want false", n)
+		}
+	}
+}
+
+func TestCarmichaelNumbers(t *testing.T) {
+	// See https://oeis.org/A002997
+	want := []int{
+		561, 1105, 1729, 2465, 2821, 6601, 8911, 10585, 15841, 29341,
+		41041, 46657, 52633, 62745, 63973, 75361,
+	}
+	cases := []struct {
+		limit int
+		want  []int
+	}{
+		{-1, []int{}},
+		{0, []int{}},
+		{560, []int{}},
+		{561, want[:1]},
+		{2000, want[:3]},
+		{2465, want[:4]},
+		{100000, want},
+	}
+	for _, c := range cases {
+		if cs := primes.CarmichaelNumbers(c.limit); !reflect.DeepEqual(cs, c.want) {
+			t.Errorf("CarmichaelNumbers(%d) == %v, want %v", c.limit, cs, c.want)
+		}
+	}
+	if cs := primes.CarmichaelNumbers(1000000); len(cs) != 43 {
+		t.Errorf("CarmichaelNumbers(1000000) returned %d numbers, want 43", len(cs))
+	}
+
+	// Check against the definition: composite numbers that pass the Fermat
+	// test for every witness coprime to them
+	cs := primes.CarmichaelNumbers(3000)
+	for n := 2; n <= 3000; n++ {
+		carmichael := !primes.IsPrime(n)
+		for a := 2; a < n && carmichael; a++ {
+			if primes.Coprime(a, n) {
+				carmichael = primes.FermatTest(n, []int{a})
+			}
+		}
+		if found := len(cs) > 0 && cs[0] == n; found != carmichael {
+			t.Errorf("CarmichaelNumbers(3000) includes %d: %v, want %v", n, found, carmichael)
+		}
+		if len(cs) > 0 && cs[0] == n {
+			cs = cs[1:]
+		}
+	}
+}
@@ -25,6 +25,7 @@
 // Call Sieve(n) to generate all prime numbers less than or equal to n,
 // IsPrime(n) to test for primality, Coprime(a,b) to test for coprimality,
 // and Pi(n) to count (or estimate) the number of primes less than or equal to n.
+// All the functions are safe for concurrent use by multiple goroutines.
 //
 // The algorithms used to implement the functions above are fairly simple;
 // they work well with relatively small primes, but they are definitely not
@@ -37,41 +38,83 @@ package primes
 import (
 	"math"
 	"sort"
+	"sync/atomic"
 )
 
-// primes is a cache of the first few prime numbers
-var primes []int
+// primeCache is a cache of the first few prime numbers: ps holds all the
+// prime numbers less than or equal to limit, the upper bound of the range
+// covered by the cache.
+// A primeCache is never modified once built, so that it can be shared by
+// any number of goroutines; SetCacheSize replaces it with a new one.
+type primeCache struct {
+	ps    []int
+	limit int
+}
+
+// cache points to the current primeCache
+var cache atomic.Pointer[primeCache]
+
+// defaultCacheLimit is the upper bound of the range covered by the cache
+// unless SetCacheSize extends it; the cache never covers less than that
+const defaultCacheLimit = 10000
 
 func init() {
 	// Cache the first 1,229 prime numbers (i.e. all primes <= 10,000)
-	primes = Sieve(10000)
+	SetCacheSize(defaultCacheLimit)
+}
+
+// cachedPrimes returns the primes in the current cache together with the
+// upper bound of the range it covers.
+// While the package is being initialized, the cache is still empty and
+// cachedPrimes returns (nil,0).
+func cachedPrimes() (primes []int, primesLimit int) {
+	if c := cache.Load(); c != nil {
+		return c.ps, c.limit
+	}
+	return nil, 0
+}
+
+// SetCacheSize regenerates the package's internal cache of primes so that it
+// holds all the primes less than or equal to n; if n is less than 10,000,
+// it restores the default cache of the primes up to 10,000.
+// A larger cache makes IsPrime a binary search for any n it covers and
+// speeds up trial division past it, and it lets Pi return exact counts for
+// any n it covers; the results of every function stay the same.
+// The cache takes about 8*n/log(n) bytes of memory (e.g. 5.3MB for
+// n == 10^7) and regenerating it sieves the range [0,n] with Sieve, which
+// temporarily takes n/2 more bytes.
+// SetCacheSize is safe to call concurrently with any other function of the
+// package: the new cache replaces the old one only once it is complete,
+// and calls already in progress finish with the old one.
+func SetCacheSize(n int) {
+	if n < defaultCacheLimit {
+		n = defaultCacheLimit
+	}
+	cache.Store(&primeCache{Sieve(n), n})
 }
 
 // Pi returns the number of primes less than or equal to n.
 // If ok is true, the result is correct; otherwise it is an estimate
-// computed as n/(log(n)-1) with an error generally below 1% (see tests).
-// This estimate is based on the prime number theorem which states that the
-// number of primes not exceeding n is asymptotic to n/log(n).
-// Better approximations are known, but they are more complex.
+// computed with Riemann's function R(n), whose relative error is below 0.6%
+// just past the range of cached primes, below 0.15% for n >= 10^5, and
+// well below 0.1% for n >= 10^7 (see tests).
+// R(n) refines the offset logarithmic integral Li(n) of the prime number
+// theorem with the correction terms for prime powers, which roughly
+// divides the error of Li(n) by three for the n Pi has to estimate.
 // See https://primes.utm.edu/howmany.html#1,
 // https://en.wikipedia.org/wiki/Prime_number_theorem, and
 // https://en.wikipedia.org/wiki/Prime-counting_function for details.
 func Pi(n int) (pi int, ok bool) {
-	// If n is smaller than or equal to the largest cached prime,
-	// we have an exact count
-	if i := sort.SearchInts(primes, n); i < len(primes) {
-		if n == primes[i] {
-			// n is the prime at index i
-			pi = i + 1
-		} else {
-			// n is not prime and primes[j] < n for all j in [0,i)
-			pi = i
-		}
+	// If n is within the range covered by the cache, we have an exact count
+	primes, primesLimit := cachedPrimes()
+	if n <= primesLimit {
+		// primes[j] <= n for all j in [0,pi)
+		pi = sort.SearchInts(primes, n+1)
 		ok = true
 	} else {
-		// n is larger than the largest prime in the cache;
+		// n is outside the range covered by the cache;
 		// use the estimate
-		pi = int(float64(n) / (math.Log(float64(n)) - 1))
+		pi = int(riemannR(float64(n)))
 	}
 	return
 }
@@ -92,6 +135,7 @@ func Pi(n int) (pi int, ok bool) {
 // See https://en.wikipedia.org/wiki/Primality_test and
 // https://en.wikipedia.org/wiki/Trial_division for details.
 func IsPrime(n int) bool {
+	primes, _ := cachedPrimes()
 	pMax := primes[len(primes)-1]
 	if n <= pMax {
 		// If n is prime, it must be in the cache
@@ -120,10 +164,93 @@ func IsPrime(n int) bool {
 
 // Coprime is a coprimality test: it returns true if the only positive integer
 // that divides evenly both a and b is 1.
+// See https://en.wikipedia.org/wiki/Coprime_integers for details.
+func Coprime(a, b int) bool {
+	// By definition, a and b are coprime if gcd(a,b) == 1
+	return GCD(a, b) == 1
+}
+
+// coprimeSetPairwiseMax is the size of the largest set for which
+// CoprimeSet checks all pairs with GCD rather than factoring the elements
+const coprimeSetPairwiseMax = 16
+
+// factorableWithCache returns true if every element of ns can be fully
+// factored by trial division with the cached primes.
+func factorableWithCache(ns []int) bool {
+	_, primesLimit := cachedPrimes()
+	bound := math.MaxInt
+	if primesLimit <= bound/primesLimit {
+		bound = primesLimit * primesLimit
+	}
+	for _, n := range ns {
+		if n > bound || n < -bound {
+			return false
+		}
+	}
+	return true
+}
+
+// CoprimeSet is a mutual coprimality test: it returns true if every pair of
+// elements of ns is coprime (see Coprime).
+// Empty and singleton lists are trivially coprime; a list with duplicates is
+// coprime only if the duplicates are 1 or -1, since Coprime(n,n) is false
+// for any other n.
+// For short lists, CoprimeSet simply tests every pair of elements.
+// Longer lists whose elements are all small enough to be factored with the
+// cached primes alone (|n| <= 10000^2 with the default cache) are instead
+// handled by factoring each element and failing as soon as a prime factor
+// shows up twice, which takes time linear in the length of ns rather than
+// quadratic; any larger element falls back to the pairwise test, since
+// factoring it could take far longer than a handful of GCDs.
+func CoprimeSet(ns []int) bool {
+	if len(ns) <= coprimeSetPairwiseMax || !factorableWithCache(ns) {
+		for i, a := range ns {
+			for _, b := range ns[i+1:] {
+				if !Coprime(a, b) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	seen := make(map[int]bool)
+	zeros, others := 0, 0
+	for _, n := range ns {
+		switch n {
+		case 0:
+			// 0 is divisible by every prime
+			zeros++
+			continue
+		case 1, -1:
+			continue
+		}
+		others++
+		coprime := true
+		factorizeAbs(n, func(p, k int) bool {
+			if seen[p] {
+				coprime = false
+			}
+			seen[p] = true
+			return coprime
+		})
+		if !coprime {
+			return false
+		}
+	}
+	// Only 1 and -1 are coprime to 0 and 0 is not coprime to itself
+	return zeros == 0 || zeros == 1 && others == 0
+}
+
+// GCD returns the greatest common divisor of a and b, that is the largest
+// positive integer that divides evenly both a and b.
+// The result is never negative and GCD(0,0) == 0, with one exception:
+// GCD(math.MinInt,0), GCD(0,math.MinInt), and GCD(math.MinInt,math.MinInt)
+// return math.MinInt, since the absolute value of math.MinInt does not fit
+// in an int.
 // This function implements the division-based version of the Euclidean algorithm.
-// See https://en.wikipedia.org/wiki/Coprime_integers and
+// See https://en.wikipedia.org/wiki/Greatest_common_divisor and
 // https://en.wikipedia.org/wiki/Euclidean_algorithm for details.
-func Coprime(a, b int) bool {
+func GCD(a, b int) int {
 	// Set a to gcd(a,b)
 	var t int
 	for b != 0 {
@@ -131,8 +258,58 @@ func Coprime(a, b int) bool {
 		b = a % b
 		a = t
 	}
-	// By definition, a and b are coprime if gcd(a,b) == 1
-	return a == 1
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// ExtendedGCD returns the greatest common divisor g of a and b together
+// with a pair of integers x and y such that a*x + b*y == g
+// (see https://en.wikipedia.org/wiki/B%C3%A9zout%27s_identity).
+// As with GCD, g is never negative and ExtendedGCD(0,0) == (0,0,0), with
+// one exception: ExtendedGCD(math.MinInt,0), ExtendedGCD(0,math.MinInt),
+// and ExtendedGCD(math.MinInt,math.MinInt) return g == math.MinInt, since
+// the absolute value of math.MinInt does not fit in an int.
+// The coefficients are the small ones found by the Euclidean algorithm:
+// |x| <= max(|b/g|,1) and |y| <= max(|a/g|,1) for any signs of a and b,
+// including zero.
+// This function implements the extended Euclidean algorithm.
+// See https://en.wikipedia.org/wiki/Extended_Euclidean_algorithm for details.
+func ExtendedGCD(a, b int) (g, x, y int) {
+	// Keep r0 == a*x0 + b*y0 and r1 == a*x1 + b*y1 at every step
+	r0, r1 := a, b
+	x0, x1 := 1, 0
+	y0, y1 := 0, 1
+	for r1 != 0 {
+		q := r0 / r1
+		r0, r1 = r1, r0-q*r1
+		x0, x1 = x1, x0-q*x1
+		y0, y1 = y1, y0-q*y1
+	}
+	if r0 < 0 {
+		return -r0, -x0, -y0
+	}
+	return r0, x0, y0
+}
+
+// LCM returns the least common multiple of a and b, that is the smallest
+// positive integer that is divisible by both a and b.
+// The result is never negative and LCM(a,0) == LCM(0,b) == 0, unless the
+// least common multiple does not fit in an int, in which case the result
+// is meaningless; for example, LCM(math.MinInt,1) == math.MinInt.
+// It is computed as a/GCD(a,b)*b so that no intermediate result is
+// larger than the final one.
+// See https://en.wikipedia.org/wiki/Least_common_multiple for details.
+func LCM(a, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	l := a / GCD(a, b) * b
+	if l < 0 {
+		return -l
+	}
+	return l
 }
 
 // Sieve returns a list of the prime numbers less than or equal to n.
@@ -149,16 +326,106 @@ func Coprime(a, b int) bool {
 //
 // Sieve takes O(n) memory and runs in O(n log log n) time.
 func Sieve(n int) []int {
+	return SieveInto(n, nil)
+}
+
+// SieveFunc calls f(p) for each prime number p less than or equal to n,
+// in increasing order.
+// It uses the same algorithm as Sieve, but it never stores the list of
+// primes, so it is preferable when the primes can be consumed one at a time
+// (e.g. written to a file or accumulated into a custom data structure).
+// SieveFunc still takes O(n) memory for the sieve itself, but it saves the
+// roughly n/log(n) ints that Sieve needs for its result.
+func SieveFunc(n int, f func(p int)) {
+	if n < 2 {
+		return
+	}
+	f(2)
+	if n == 2 {
+		return
+	}
+	for i, composite := range oddSieve(n) {
+		if !composite {
+			f(2*i + 3)
+		}
+	}
+}
+
+// FirstPrimes returns a list of the first k primes in increasing order;
+// for example, FirstPrimes(6) returns [2 3 5 7 11 13].
+// It sieves up to Rosser's upper bound for the k-th prime, that is
+// k*(log(k)+log(log(k))) for k >= 6, and truncates the result; should the
+// bound ever fall short, it grows it and sieves again.
+// If k is less than 1, it returns an empty list.
+// See https://en.wikipedia.org/wiki/Prime_number_theorem#Approximations_for_the_nth_prime_number
+// for details.
+func FirstPrimes(k int) []int {
+	if k < 1 {
+		return []int{}
+	}
+	n := 13 // the 6th prime
+	if k >= 6 {
+		x := float64(k)
+		n = int(x * (math.Log(x) + math.Log(math.Log(x))))
+	}
+	ps := Sieve(n)
+	for len(ps) < k {
+		n += n / 16
+		ps = Sieve(n)
+	}
+	return ps[:k]
+}
+
+// SieveInto is like Sieve, but it runs the sieve in the scratch table buf
+// provided by the caller rather than allocating one, so that a loop calling
+// it repeatedly needs to allocate only the lists of primes it returns.
+// The table needs (n-1)/2 elements, so make([]bool, n/2) is always enough;
+// if buf is shorter than that, SieveInto allocates a table of its own as
+// Sieve does. A buf sized for the largest n needed can be reused for any
+// smaller n, since SieveInto clears the part of it that it uses.
+func SieveInto(n int, buf []bool) []int {
 	switch {
 	case n < 2:
 		return []int{}
 	case n == 2:
 		return []int{2}
 	}
+	a := oddSieveInto(n, buf)
+	// ps will store the computed primes; its initial capacity is an upper
+	// bound on the prime-counting function pi(n), so that it never grows
+	_, _, upper := PiWithBounds(n)
+	ps := make([]int, 1, upper)
+	ps[0] = 2
+	for i := 0; i < len(a); i++ {
+		if !a[i] {
+			ps = append(ps, 2*i+3)
+		}
+	}
+	return ps
+}
+
+// oddSieve runs the sieve of Eratosthenes over the odd numbers in [3,n],
+// for n >= 3, and returns a table a such that a[i] == false if and only if
+// 2*i+3 is prime.
+func oddSieve(n int) []bool {
+	return oddSieveInto(n, nil)
+}
+
+// oddSieveInto is like oddSieve, but it builds the table in buf if buf is
+// long enough.
+func oddSieveInto(n int, buf []bool) []bool {
 	// a[i] == false ==> p=2*i+3 is a candidate prime
 	// p in [3,n] ==> i in [0,(n-3)/2]
 	length := 1 + (n-3)/2
-	a := make([]bool, length, length)
+	var a []bool
+	if len(buf) >= length {
+		a = buf[:length]
+		for i := range a {
+			a[i] = false
+		}
+	} else {
+		a = make([]bool, length, length)
+	}
 	// Start with number 3 and consider only odd numbers
 	sqrtn := int(math.Sqrt(float64(n)))
 	for i, p := 0, 3; p <= sqrtn; p += 2 {
@@ -170,15 +437,5 @@ func Sieve(n int) []int {
 		}
 		i++
 	}
-	// ps will store the computed primes; its initial capacity is based
-	// an estimate of the prime-counting function pi(n)
-	pi, _ := Pi(n)
-	ps := make([]int, 1, pi)
-	ps[0] = 2
-	for i := 0; i < length; i++ {
-		if !a[i] {
-			ps = append(ps, 2*i+3)
-		}
-	}
-	return ps
+	return a
 }
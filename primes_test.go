@@ -24,43 +24,100 @@ package primes_test
 
 import (
 	"math"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"sort"
+	"sync"
 	"testing"
 
 	"github.com/fxtlabs/primes"
 )
 
+// contiguousPrimes lists a few runs of consecutive prime numbers: every
+// number strictly between two adjacent entries of a run is composite.
+var contiguousPrimes = [][]int{
+	{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47},
+	{127, 131, 137, 139, 149, 151, 157, 163, 167, 173, 179, 181},
+	{877, 881, 883, 887, 907, 911, 919, 929, 937, 941, 947, 953},
+	{2089, 2099, 2111, 2113, 2129, 2131, 2137, 2141, 2143, 2153},
+	{9857, 9859, 9871, 9883, 9887, 9901, 9907, 9923, 9929, 9931},
+	{1000003, 1000033, 1000037},
+}
+
+// piCounts lists the exact number of primes less than or equal to a few n
+// (see https://oeis.org/A006880)
+var piCounts = []struct {
+	n    int64
+	want int64
+}{
+	{10, 4},
+	{100, 25},
+	{1000, 168},
+	{10000, 1229},
+	{100000, 9592},
+	{1000000, 78498},
+	{10000000, 664579},
+	{100000000, 5761455},
+	{1000000000, 50847534},
+	{10000000000, 455052511},
+	{100000000000, 4118054813},
+	{1000000000000, 37607912018},
+	{1000000000000000, 29844570422669},
+	{1000000000000000000, 24739954287740860},
+	{104730, 10000},
+	{10001, 1229},
+	{12345, 1474},
+	{20000, 2262},
+	{50000, 5133},
+	{70000, 6935},
+}
+
 func TestPi(t *testing.T) {
-	cases := []struct {
-		n    int
-		want int
-	}{
-		{10, 4},
-		{100, 25},
-		{1000, 168},
-		{10000, 1229},
-		{100000, 9592},
-		{1000000, 78498},
-		{10000000, 664579},
-		{100000000, 5761455},
-		{1000000000, 50847534},
-		{104730, 10000},
-	}
-	// Maximum relative error when Pi(n) returns an estimate
-	const epsMax = 0.01
-	for _, c := range cases {
-		pi, ok := primes.Pi(c.n)
+	for _, c := range piCounts {
+		n := int(c.n)
+		if int64(n) != c.n {
+			// n does not fit in an int on this platform
+			continue
+		}
+		pi, ok := primes.Pi(n)
 		if ok {
-			if pi != c.want {
+			if int64(pi) != c.want {
 				t.Errorf("Pi(%d) == (%d,true), want %d", c.n, pi, c.want)
 			}
 		} else {
-			eps := math.Abs(float64(pi-c.want) / float64(c.want))
+			// Maximum relative error when Pi(n) returns an estimate
+			epsMax := piEpsMax(n)
+			eps := math.Abs(float64(int64(pi)-c.want) / float64(c.want))
 			if eps >= epsMax {
 				t.Errorf("Pi(%d) == (%d,false), want %d; eps=%f", c.n, pi, c.want, eps)
 			}
 		}
 
 	}
+
+	// Check the estimate just past the range of cached primes
+	ps := primes.Sieve(100000)
+	for n := 10001; n <= 100000; n += 97 {
+		want := sort.SearchInts(ps, n+1)
+		pi, _ := primes.Pi(n)
+		eps := math.Abs(float64(pi-want) / float64(want))
+		if eps >= piEpsMax(n) {
+			t.Errorf("Pi(%d) == %d, want %d; eps=%f", n, pi, want, eps)
+		}
+	}
+}
+
+// piEpsMax returns the maximum relative error of Pi(n) when it returns an
+// estimate
+func piEpsMax(n int) float64 {
+	switch {
+	case n < 100000:
+		return 0.006
+	case n < 10000000:
+		return 0.0015
+	}
+	return 0.001
 }
 
 func TestIsPrime(t *testing.T) {
@@ -87,14 +144,6 @@ func TestIsPrime(t *testing.T) {
 
 	// Check against each continguous sequence of primes that the primes
 	// are classified as primes and the numbers in between as not.
-	contiguousPrimes := [][]int{
-		{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47},
-		{127, 131, 137, 139, 149, 151, 157, 163, 167, 173, 179, 181},
-		{877, 881, 883, 887, 907, 911, 919, 929, 937, 941, 947, 953},
-		{2089, 2099, 2111, 2113, 2129, 2131, 2137, 2141, 2143, 2153},
-		{9857, 9859, 9871, 9883, 9887, 9901, 9907, 9923, 9929, 9931},
-		{1000003, 1000033, 1000037},
-	}
 	for _, ps := range contiguousPrimes {
 		for _, p := range ps {
 			if !primes.IsPrime(p) {
@@ -124,6 +173,118 @@ func TestIsPrime(t *testing.T) {
 	}
 }
 
+func TestSetCacheSize(t *testing.T) {
+	const n = 1000000
+	// Record a few results with the default cache
+	ns := []int{0, 1, 2, 9973, 10007, 20011, 1000003, 9973 * 10007, math.MaxInt32}
+	for i := 0; i < 1000; i++ {
+		ns = append(ns, rand.Intn(math.MaxInt32))
+	}
+	isPrime := make([]bool, len(ns))
+	for i, m := range ns {
+		isPrime[i] = primes.IsPrime(m)
+	}
+	pis := make([]int, 0, 100)
+	for m := 10000000; m <= 1000000000; m += 10000000 {
+		pi, _ := primes.Pi(m)
+		pis = append(pis, pi)
+	}
+
+	primes.SetCacheSize(n)
+	defer primes.SetCacheSize(0)
+
+	// IsPrime must not change, within the cache or beyond it
+	ps := primes.Sieve(n + 1000)
+	for m, i := 0, 0; m <= n+1000; m++ {
+		want := i < len(ps) && ps[i] == m
+		if want {
+			i++
+		}
+		if p := primes.IsPrime(m); p != want {
+			t.Errorf("IsPrime(%d) == %v, want %v", m, p, want)
+		}
+	}
+	for i, m := range ns {
+		if p := primes.IsPrime(m); p != isPrime[i] {
+			t.Errorf("IsPrime(%d) == %v, want %v", m, p, isPrime[i])
+		}
+	}
+	// Pi must be exact within the cache and unchanged beyond it
+	for m := 0; m <= n; m += 999 {
+		want := sort.SearchInts(ps, m+1)
+		if pi, ok := primes.Pi(m); pi != want || !ok {
+			t.Errorf("Pi(%d) == (%d,%v), want (%d,true)", m, pi, ok, want)
+		}
+	}
+	for i, m := 0, 10000000; m <= 1000000000; i, m = i+1, m+10000000 {
+		if pi, ok := primes.Pi(m); pi != pis[i] || ok {
+			t.Errorf("Pi(%d) == (%d,%v), want (%d,false)", m, pi, ok, pis[i])
+		}
+	}
+
+	// Shrinking the cache restores the default one
+	primes.SetCacheSize(0)
+	if pi, ok := primes.Pi(10000); pi != 1229 || !ok {
+		t.Errorf("Pi(10000) == (%d,%v), want (1229,true)", pi, ok)
+	}
+	if _, ok := primes.Pi(10001); ok {
+		t.Errorf("Pi(10001) is exact after restoring the default cache")
+	}
+	for i, m := range ns {
+		if p := primes.IsPrime(m); p != isPrime[i] {
+			t.Errorf("IsPrime(%d) == %v, want %v", m, p, isPrime[i])
+		}
+	}
+	if !primes.IsPrimeBig(big.NewInt(20011)) {
+		t.Errorf("IsPrimeBig(20011) == false, want true")
+	}
+}
+
+// TestConcurrentUse hammers IsPrime, Pi, and Sieve from many goroutines
+// while the cache is being resized; run it with go test -race to check
+// for data races.
+func TestConcurrentUse(t *testing.T) {
+	defer primes.SetCacheSize(0)
+	const n = 200000
+	want := primes.Sieve(n)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			switch g {
+			case 0:
+				// Keep resizing the cache under the feet of the others
+				for i := 0; i < 20; i++ {
+					primes.SetCacheSize(10000 + i%4*50000)
+				}
+			case 1, 2, 3:
+				for m, i := g, 0; m <= n; m += 7 {
+					for i < len(want) && want[i] < m {
+						i++
+					}
+					if p := primes.IsPrime(m); p != (i < len(want) && want[i] == m) {
+						t.Errorf("IsPrime(%d) == %v", m, p)
+					}
+				}
+			case 4, 5:
+				for m := g; m <= 10000; m += 3 {
+					if pi, ok := primes.Pi(m); !ok || pi != sort.SearchInts(want, m+1) {
+						t.Errorf("Pi(%d) == (%d,%v), want (%d,true)", m, pi, ok, sort.SearchInts(want, m+1))
+					}
+				}
+			default:
+				for i := 0; i < 10; i++ {
+					if ps := primes.Sieve(n); !reflect.DeepEqual(ps, want) {
+						t.Errorf("Sieve(%d) returned a different list", n)
+					}
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
 func TestCoprime(t *testing.T) {
 	ps := []int{
 		2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47,
@@ -164,6 +325,198 @@ func TestCoprime(t *testing.T) {
 	}
 }
 
+func TestCoprimeSet(t *testing.T) {
+	cases := []struct {
+		ns   []int
+		want bool
+	}{
+		{nil, true},
+		{[]int{}, true},
+		{[]int{0}, true},
+		{[]int{6}, true},
+		{[]int{2, 3, 5}, true},
+		{[]int{6, 10, 15}, false},
+		{[]int{4, 9, 25, 49}, true},
+		{[]int{-4, 9, -25, 49}, true},
+		{[]int{4, 9, 25, 49, 14}, false},
+		{[]int{1, 1, 1}, true},
+		{[]int{-1, 1, 7, 7}, false},
+		{[]int{0, 1, -1}, true},
+		{[]int{0, 0}, false},
+		{[]int{0, 5}, false},
+	}
+	for _, c := range cases {
+		if got := primes.CoprimeSet(c.ns); got != c.want {
+			t.Errorf("CoprimeSet(%v) == %v, want %v", c.ns, got, c.want)
+		}
+	}
+
+	// Check longer lists against a test of every pair
+	pairwise := func(ns []int) bool {
+		for i, a := range ns {
+			for _, b := range ns[i+1:] {
+				if !primes.Coprime(a, b) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	ps := primes.Sieve(1000)
+	for i := 0; i < 1000; i++ {
+		ns := make([]int, 10+rand.Intn(40))
+		for j := range ns {
+			switch rand.Intn(20) {
+			case 0:
+				ns[j] = rand.Intn(3) - 1
+			case 1:
+				ns[j] = -ps[rand.Intn(len(ps))]
+			default:
+				ns[j] = ps[rand.Intn(len(ps))]
+				if rand.Intn(10) == 0 {
+					ns[j] *= ps[rand.Intn(len(ps))]
+				}
+			}
+		}
+		if got, want := primes.CoprimeSet(ns), pairwise(ns); got != want {
+			t.Errorf("CoprimeSet(%v) == %v, want %v", ns, got, want)
+		}
+	}
+
+	// A long list of distinct primes is coprime, until a multiple of one
+	// of them is added to it
+	if !primes.CoprimeSet(ps) {
+		t.Errorf("CoprimeSet(Sieve(1000)) == false, want true")
+	}
+	if primes.CoprimeSet(append(ps, 2*997)) {
+		t.Errorf("CoprimeSet(append(Sieve(1000),2*997)) == true, want false")
+	}
+
+	// Elements too large to factor with the cache alone fall back to the
+	// pairwise test
+	large := make([]int, 17)
+	p := 1 << 30
+	for i := range large {
+		p = primes.NextPrimeInclusive(p + 1)
+		large[i] = p
+	}
+	if !primes.CoprimeSet(large) {
+		t.Errorf("CoprimeSet(%v) == false, want true", large)
+	}
+	large = append(large, large[3])
+	if primes.CoprimeSet(large) {
+		t.Errorf("CoprimeSet(%v) == true, want false", large)
+	}
+}
+
+func TestGCD(t *testing.T) {
+	cases := []struct {
+		a, b int
+		want int
+	}{
+		{0, 0, 0},
+		{0, 5, 5},
+		{5, 0, 5},
+		{0, -5, 5},
+		{-5, 0, 5},
+		{1, 1, 1},
+		{12, 18, 6},
+		{-12, 18, 6},
+		{12, -18, 6},
+		{-12, -18, 6},
+		{17, 31, 1},
+		{9973, 9973, 9973},
+		{2 * 3 * 9931, 3 * 5 * 9931, 3 * 9931},
+		{math.MaxInt32, math.MaxInt32 - 1, 1},
+		{math.MinInt, 1, 1},
+		{math.MinInt, 3, 1},
+		{math.MinInt, math.MinInt / 2, -(math.MinInt / 2)},
+		// |math.MinInt| does not fit in an int
+		{math.MinInt, 0, math.MinInt},
+		{0, math.MinInt, math.MinInt},
+		{math.MinInt, math.MinInt, math.MinInt},
+	}
+	for _, c := range cases {
+		g := primes.GCD(c.a, c.b)
+		if g != c.want {
+			t.Errorf("GCD(%d,%d) == %d, want %d", c.a, c.b, g, c.want)
+		}
+	}
+}
+
+func TestExtendedGCD(t *testing.T) {
+	// bound returns the largest coefficient allowed against n, that is
+	// max(|n|,1)
+	bound := func(n int) int {
+		if n < 0 {
+			n = -n
+		}
+		if n < 1 {
+			return 1
+		}
+		return n
+	}
+	check := func(a, b int) {
+		g, x, y := primes.ExtendedGCD(a, b)
+		if want := primes.GCD(a, b); g != want {
+			t.Errorf("ExtendedGCD(%d,%d) == (%d,%d,%d), want g=%d", a, b, g, x, y, want)
+		}
+		if a*x+b*y != g {
+			t.Errorf("ExtendedGCD(%d,%d) == (%d,%d,%d), but %d*%d + %d*%d != %d", a, b, g, x, y, a, x, b, y, g)
+		}
+		// The coefficients are no larger than needed, so they cannot
+		// overflow
+		if g != 0 && (x > bound(b/g) || -x > bound(b/g) || y > bound(a/g) || -y > bound(a/g)) {
+			t.Errorf("ExtendedGCD(%d,%d) == (%d,%d,%d): coefficients too large", a, b, g, x, y)
+		}
+	}
+	for a := -100; a <= 100; a++ {
+		for b := -100; b <= 100; b++ {
+			check(a, b)
+		}
+	}
+	for i := 0; i < 10000; i++ {
+		check(rand.Intn(math.MaxInt32)-math.MaxInt32/2, rand.Intn(math.MaxInt32)-math.MaxInt32/2)
+	}
+}
+
+func TestLCM(t *testing.T) {
+	cases := []struct {
+		a, b int
+		want int
+	}{
+		{0, 0, 0},
+		{0, 5, 0},
+		{5, 0, 0},
+		{1, 1, 1},
+		{4, 6, 12},
+		{-4, 6, 12},
+		{4, -6, 12},
+		{-4, -6, 12},
+		{21, 6, 42},
+		{9973, 9973, 9973},
+		{math.MinInt / 2, 2, -(math.MinInt / 2)},
+		// The least common multiple does not fit in an int
+		{math.MinInt, 1, math.MinInt},
+		{math.MinInt, -1, math.MinInt},
+	}
+	for _, c := range cases {
+		l := primes.LCM(c.a, c.b)
+		if l != c.want {
+			t.Errorf("LCM(%d,%d) == %d, want %d", c.a, c.b, l, c.want)
+		}
+	}
+
+	// The LCM of two distinct primes is their product
+	ps := primes.Sieve(10000)
+	for i := 1; i < len(ps); i += 97 {
+		p, q := ps[i-1], ps[i]
+		if l := primes.LCM(p, q); l != p*q {
+			t.Errorf("LCM(%d,%d) == %d, want %d", p, q, l, p*q)
+		}
+	}
+}
+
 func TestSieve(t *testing.T) {
 	cases := []struct {
 		n    int // input to Sieve(n)
@@ -199,3 +552,63 @@ func TestSieve(t *testing.T) {
 		}
 	}
 }
+
+func TestSieveFunc(t *testing.T) {
+	for _, n := range []int{-1, 0, 1, 2, 3, 4, 100, 9973, 10000, 1000000} {
+		ps := []int{}
+		primes.SieveFunc(n, func(p int) {
+			ps = append(ps, p)
+		})
+		if want := primes.Sieve(n); !reflect.DeepEqual(ps, want) {
+			t.Errorf("SieveFunc(%d) produced %d primes, want %d", n, len(ps), len(want))
+		}
+	}
+}
+
+func TestSieveInto(t *testing.T) {
+	// Reuse one buffer for decreasing and increasing n alike, and try
+	// buffers that are too short to be used
+	buf := make([]bool, 500000)
+	for _, n := range []int{1000000, 10, 999999, 2, 100, -1, 3, 4, 5, 100000, 1000001, 2000000} {
+		if ps, want := primes.SieveInto(n, buf), primes.Sieve(n); !reflect.DeepEqual(ps, want) {
+			t.Errorf("SieveInto(%d,buf) returned %d primes, want %d", n, len(ps), len(want))
+		}
+		if ps, want := primes.SieveInto(n, nil), primes.Sieve(n); !reflect.DeepEqual(ps, want) {
+			t.Errorf("SieveInto(%d,nil) returned %d primes, want %d", n, len(ps), len(want))
+		}
+	}
+}
+
+func TestFirstPrimes(t *testing.T) {
+	cases := []struct {
+		k    int
+		want []int
+	}{
+		{-1, []int{}},
+		{0, []int{}},
+		{1, []int{2}},
+		{2, []int{2, 3}},
+		{5, []int{2, 3, 5, 7, 11}},
+		{6, []int{2, 3, 5, 7, 11, 13}},
+		{7, []int{2, 3, 5, 7, 11, 13, 17}},
+	}
+	for _, c := range cases {
+		if ps := primes.FirstPrimes(c.k); !reflect.DeepEqual(ps, c.want) {
+			t.Errorf("FirstPrimes(%d) == %v, want %v", c.k, ps, c.want)
+		}
+	}
+
+	// FirstPrimes(1229) lists all the primes up to 10,000
+	ps := primes.FirstPrimes(1229)
+	if len(ps) != 1229 || ps[len(ps)-1] != 9973 {
+		t.Errorf("FirstPrimes(1229) ends at %d, want 9973", ps[len(ps)-1])
+	}
+
+	// Check against Sieve
+	all := primes.Sieve(1000000)
+	for k := 1; k <= len(all); k += 4999 {
+		if ps := primes.FirstPrimes(k); !reflect.DeepEqual(ps, all[:k]) {
+			t.Errorf("FirstPrimes(%d) ends at %d, want %d", k, ps[len(ps)-1], all[k-1])
+		}
+	}
+}
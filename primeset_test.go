@@ -0,0 +1,65 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestPrimeSet(t *testing.T) {
+	for _, n := range []int{-1, 0, 1, 2, 3, 4, 63, 64, 127, 128, 129, 10000, 100003} {
+		s := primes.NewPrimeSet(n)
+		// Contains must agree with IsPrime over [0,n] and be false outside
+		for x := -2; x <= n+2; x++ {
+			want := x <= n && primes.IsPrime(x)
+			if c := s.Contains(x); c != want {
+				t.Errorf("NewPrimeSet(%d).Contains(%d) == %v, want %v", n, x, c, want)
+			}
+		}
+		ps, want := s.Slice(), primes.Sieve(n)
+		if len(ps) != len(want) {
+			t.Errorf("NewPrimeSet(%d).Slice(): len == %d, want %d", n, len(ps), len(want))
+			continue
+		}
+		for i, p := range ps {
+			if p != want[i] {
+				t.Errorf("NewPrimeSet(%d).Slice(): [%d] == %d, want %d", n, i, p, want[i])
+				break
+			}
+		}
+	}
+
+	// Check a larger set against the sieve only where it is cheap to do so
+	const n = 10000000
+	s := primes.NewPrimeSet(n)
+	if c := len(s.Slice()); c != 664579 {
+		t.Errorf("len(NewPrimeSet(%d).Slice()) == %d, want 664579", n, c)
+	}
+	for _, x := range []int{9999991, 9999973, 9999999, n, n + 19} {
+		if c, want := s.Contains(x), x <= n && primes.IsPrime(x); c != want {
+			t.Errorf("NewPrimeSet(%d).Contains(%d) == %v, want %v", n, x, c, want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+import (
+	"math"
+	"math/big"
+)
+
+// Primorial returns the primorial n#, that is the product of all the primes
+// less than or equal to n; for example, Primorial(5) == 2*3*5 == 30.
+// Primorials grow about as fast as e^n, so the product is computed and
+// returned as a big.Int.
+// If n is less than 2, it returns 1 (the empty product).
+// See https://en.wikipedia.org/wiki/Primorial for details.
+func Primorial(n int) *big.Int {
+	return product(Sieve(n))
+}
+
+// PrimorialNth returns the product of the first k primes; for example,
+// PrimorialNth(3) == 2*3*5 == 30.
+// If k is less than 1, it returns 1 (the empty product).
+func PrimorialNth(k int) *big.Int {
+	return product(FirstPrimes(k))
+}
+
+// DividesPrimorial returns true if n divides the primorial bound# (see
+// Primorial), which is the case exactly when n is squarefree and
+// bound-smooth; for example, DividesPrimorial(30,5) is true since
+// 5# == 30, while DividesPrimorial(12,5) is false since 12 is divisible
+// by 4.
+// It checks the two conditions with IsSquareFree and IsSmooth rather than
+// computing the primorial itself.
+// If n is less than 1, it returns false.
+func DividesPrimorial(n, bound int) bool {
+	return IsSquareFree(n) && IsSmooth(n, bound)
+}
+
+// product returns the product of the given primes as a big.Int.
+// The primes are multiplied together in groups whose product fits in a
+// uint64, so that most multiplications are done on machine words.
+func product(ps []int) *big.Int {
+	prod := big.NewInt(1)
+	group := new(big.Int)
+	var g uint64 = 1
+	for _, p := range ps {
+		if g > math.MaxUint64/uint64(p) {
+			prod.Mul(prod, group.SetUint64(g))
+			g = 1
+		}
+		g *= uint64(p)
+	}
+	return prod.Mul(prod, group.SetUint64(g))
+}
@@ -0,0 +1,134 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestPrimorial(t *testing.T) {
+	// See https://oeis.org/A034386
+	cases := []struct {
+		n    int
+		want string
+	}{
+		{-1, "1"},
+		{0, "1"},
+		{1, "1"},
+		{2, "2"},
+		{4, "6"},
+		{5, "30"},
+		{10, "210"},
+		{30, "6469693230"},
+		{50, "614889782588491410"},
+		{60, "1922760350154212639070"},
+	}
+	for _, c := range cases {
+		p := primes.Primorial(c.n)
+		if p.String() != c.want {
+			t.Errorf("Primorial(%d) == %v, want %s", c.n, p, c.want)
+		}
+	}
+
+	// Check against a plain product of the primes
+	want := big.NewInt(1)
+	for n := 2; n <= 3000; n++ {
+		if primes.IsPrime(n) {
+			want.Mul(want, big.NewInt(int64(n)))
+		}
+		if n%100 == 0 {
+			if p := primes.Primorial(n); p.Cmp(want) != 0 {
+				t.Errorf("Primorial(%d) == %v, want %v", n, p, want)
+			}
+		}
+	}
+}
+
+func TestPrimorialNth(t *testing.T) {
+	// See https://oeis.org/A002110
+	cases := []struct {
+		k    int
+		want string
+	}{
+		{-1, "1"},
+		{0, "1"},
+		{1, "2"},
+		{2, "6"},
+		{3, "30"},
+		{5, "2310"},
+		{6, "30030"},
+		{10, "6469693230"},
+		{15, "614889782588491410"},
+	}
+	for _, c := range cases {
+		p := primes.PrimorialNth(c.k)
+		if p.String() != c.want {
+			t.Errorf("PrimorialNth(%d) == %v, want %s", c.k, p, c.want)
+		}
+	}
+
+	// The product of the first k primes is the primorial of the k-th prime
+	ps := primes.Sieve(100000)
+	for k := 1; k <= len(ps); k += 1 + k/2 {
+		want := primes.Primorial(ps[k-1])
+		if p := primes.PrimorialNth(k); p.Cmp(want) != 0 {
+			t.Errorf("PrimorialNth(%d) != Primorial(%d)", k, ps[k-1])
+		}
+	}
+}
+
+func TestDividesPrimorial(t *testing.T) {
+	cases := []struct {
+		n, bound int
+		want     bool
+	}{
+		{-30, 5, false},
+		{0, 5, false},
+		{1, 0, true},
+		{30, 5, true},
+		{30, 6, true},
+		{30, 4, false},
+		{12, 5, false},
+		{7, 5, false},
+		{2 * 3 * 5 * 7 * 11 * 13 * 17 * 19 * 23, 23, true},
+	}
+	for _, c := range cases {
+		if d := primes.DividesPrimorial(c.n, c.bound); d != c.want {
+			t.Errorf("DividesPrimorial(%d,%d) == %v, want %v", c.n, c.bound, d, c.want)
+		}
+	}
+
+	// Check against the remainder of the division of the primorial by n
+	for bound := 0; bound <= 20; bound++ {
+		p := primes.Primorial(bound)
+		for n := 1; n <= 10000; n++ {
+			want := new(big.Int).Mod(p, big.NewInt(int64(n))).Sign() == 0
+			if d := primes.DividesPrimorial(n, bound); d != want {
+				t.Errorf("DividesPrimorial(%d,%d) == %v, want %v", n, bound, d, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+// maxProgressReports is the number of progress reports SieveProgress aims
+// for, however large n is
+const maxProgressReports = 100
+
+// SieveProgress is like Sieve, but it calls report(done,total) from time to
+// time to tell how far it has got, so that a user interface can show a
+// progress bar for a long sieve: done is the number up to which all the
+// primes have been found and total is n.
+// The primes are found with a segmented sieve of Eratosthenes and report is
+// called after a segment only once done has advanced by at least 1% of n
+// since the last call, so it is called at most about maxProgressReports
+// times, with increasing values of done; the last call is always
+// report(n,n), just before SieveProgress returns.
+// If n is less than 2, it returns an empty list without calling report.
+func SieveProgress(n int, report func(done, total int)) []int {
+	if n < 2 {
+		return []int{}
+	}
+	pi, _ := Pi(n)
+	ps := make([]int, 1, pi)
+	ps[0] = 2
+	step := n / maxProgressReports
+	last := 2
+	sieveSegments(3, int64(n), func(base int64, a []bool) bool {
+		for i, composite := range a {
+			if !composite {
+				ps = append(ps, int(base)+2*i)
+			}
+		}
+		// The segment covers all the numbers up to its last odd candidate
+		// (up to n if it is the last segment)
+		done := int(base) + 2*len(a) - 1
+		if done > n {
+			done = n
+		}
+		if done-last >= step && done < n {
+			report(done, n)
+			last = done
+		}
+		return true
+	})
+	report(n, n)
+	return ps
+}
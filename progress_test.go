@@ -0,0 +1,66 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestSieveProgress(t *testing.T) {
+	for _, n := range []int{-1, 0, 1, 2, 3, 100, 65536, 65537, 1000000, 10000000} {
+		calls, last := 0, 0
+		ps := primes.SieveProgress(n, func(done, total int) {
+			calls++
+			if total != n {
+				t.Errorf("SieveProgress(%d) reported total == %d", n, total)
+			}
+			if done <= last || done > total {
+				t.Errorf("SieveProgress(%d) reported done == %d after %d", n, done, last)
+			}
+			last = done
+		})
+		if want := primes.Sieve(n); !reflect.DeepEqual(ps, want) {
+			t.Errorf("SieveProgress(%d) returned %d primes, want %d", n, len(ps), len(want))
+		}
+		switch {
+		case n < 2 && calls != 0:
+			t.Errorf("SieveProgress(%d) reported progress %d times, want 0", n, calls)
+		case n >= 2 && last != n:
+			t.Errorf("SieveProgress(%d) last reported done == %d, want %d", n, last, n)
+		case calls > 101:
+			t.Errorf("SieveProgress(%d) reported progress %d times, want at most 101", n, calls)
+		}
+	}
+
+	// A long sieve reports its progress often enough to draw a progress bar
+	calls := 0
+	primes.SieveProgress(20000000, func(done, total int) {
+		calls++
+	})
+	if calls < 50 {
+		t.Errorf("SieveProgress(20000000) reported progress %d times, want at least 50", calls)
+	}
+}
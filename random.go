@@ -0,0 +1,62 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+import (
+	"math/bits"
+	"math/rand"
+)
+
+// RandomPrime returns a prime p chosen uniformly at random among the primes
+// such that lo <= p <= hi, using rng as the source of randomness so that
+// the results can be reproduced by seeding it accordingly.
+// It draws random candidates in [lo,hi] and returns the first one that
+// passes IsPrime; since the density of the primes around n is about
+// 1/log(n), only a few dozen candidates are needed on average.
+// If too many candidates in a row turn out to be composite, the range may
+// contain few or no primes, so RandomPrime falls back on listing them with
+// SieveRange and picking one of them; if there are none, ok is false.
+// RandomPrime is NOT cryptographically secure: math/rand is predictable and
+// an int is far too small for a cryptographic key anyway.
+// Use crypto/rand.Prime for that.
+func RandomPrime(lo, hi int, rng *rand.Rand) (p int, ok bool) {
+	if lo < 2 {
+		lo = 2
+	}
+	if lo > hi {
+		return 0, false
+	}
+	width := int64(hi-lo) + 1
+	// Allow for many times the expected number of attempts
+	attempts := 64 * bits.Len(uint(hi))
+	for i := 0; i < attempts; i++ {
+		if n := lo + int(rng.Int63n(width)); IsPrime(n) {
+			return n, true
+		}
+	}
+	ps := SieveRange(lo, hi)
+	if len(ps) == 0 {
+		return 0, false
+	}
+	return ps[rng.Intn(len(ps))], true
+}
@@ -0,0 +1,96 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestRandomPrime(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	// Ranges without primes
+	cases := []struct {
+		lo, hi int
+	}{
+		{-10, 1},
+		{5, 4},
+		{24, 28},
+		{114, 126},
+	}
+	for _, c := range cases {
+		if p, ok := primes.RandomPrime(c.lo, c.hi, rng); ok {
+			t.Errorf("RandomPrime(%d,%d) == (%d,true), want (_,false)", c.lo, c.hi, p)
+		}
+	}
+
+	// Every result must be a prime in range
+	ranges := []struct {
+		lo, hi int
+	}{
+		{-10, 2},
+		{2, 3},
+		{113, 127},
+		{0, 100},
+		{1000000, 2000000},
+		{1 << 30, 1<<30 + 1000},
+	}
+	for _, r := range ranges {
+		for i := 0; i < 100; i++ {
+			p, ok := primes.RandomPrime(r.lo, r.hi, rng)
+			if !ok || p < r.lo || p > r.hi || !primes.IsPrime(p) {
+				t.Errorf("RandomPrime(%d,%d) == (%d,%v), want a prime in range", r.lo, r.hi, p, ok)
+			}
+		}
+	}
+
+	// The choice is uniform among the primes in range
+	counts := map[int]int{}
+	const draws = 25000
+	for i := 0; i < draws; i++ {
+		p, _ := primes.RandomPrime(0, 100, rng)
+		counts[p]++
+	}
+	if len(counts) != 25 {
+		t.Errorf("RandomPrime(0,100) returned %d distinct primes, want 25", len(counts))
+	}
+	for p, n := range counts {
+		if n < draws/25*8/10 || n > draws/25*12/10 {
+			t.Errorf("RandomPrime(0,100) returned %d %d times out of %d", p, n, draws)
+		}
+	}
+
+	// The same seed gives the same results
+	a := rand.New(rand.NewSource(42))
+	b := rand.New(rand.NewSource(42))
+	for i := 0; i < 10; i++ {
+		p, _ := primes.RandomPrime(0, 1000000, a)
+		q, _ := primes.RandomPrime(0, 1000000, b)
+		if p != q {
+			t.Errorf("RandomPrime(0,1000000) == %d and %d with the same seed", p, q)
+		}
+	}
+}
@@ -0,0 +1,360 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+import (
+	"math"
+	"sort"
+)
+
+// luckyNumbers returns the lucky numbers less than or equal to n in
+// increasing order.
+// The lucky numbers are the survivors of a sieve that starts from the odd
+// numbers and then, for each surviving number k > 1 taken in increasing
+// order, removes every k-th number still in the list.
+// Each pass still moves most of the list, so the sieve takes
+// O(n^2/log^2(n)) time and only suits moderate values of n.
+// See https://en.wikipedia.org/wiki/Lucky_number for details.
+func luckyNumbers(n int) []int {
+	if n < 1 {
+		return []int{}
+	}
+	ls := make([]int, 0, (n+1)/2)
+	for k := 1; k <= n; k += 2 {
+		ls = append(ls, k)
+	}
+	for i := 1; i < len(ls) && ls[i] <= len(ls); i++ {
+		// Remove every k-th number, compacting the list in place; the
+		// first k-1 numbers stay where they are and each run of numbers
+		// between two removed ones is moved down in a single copy
+		k := ls[i]
+		j := k - 1
+		for m := k - 1; m < len(ls); m += k {
+			end := m + k
+			if end > len(ls) {
+				end = len(ls)
+			}
+			j += copy(ls[j:], ls[m+1:end])
+		}
+		ls = ls[:j]
+	}
+	return ls
+}
+
+// LuckyPrimes returns the numbers less than or equal to n that are both
+// prime and lucky (see https://en.wikipedia.org/wiki/Lucky_number),
+// in increasing order.
+// It intersects the output of Sieve(n) with the list of lucky numbers
+// generated by the lucky number sieve.
+func LuckyPrimes(n int) []int {
+	ls := luckyNumbers(n)
+	lps := []int{}
+	i := 0
+	for _, p := range Sieve(n) {
+		for i < len(ls) && ls[i] < p {
+			i++
+		}
+		if i == len(ls) {
+			break
+		}
+		if ls[i] == p {
+			lps = append(lps, p)
+		}
+	}
+	return lps
+}
+
+// maxDigitPrimesDigits is the largest number of digits accepted by
+// DigitPrimes
+const maxDigitPrimesDigits = 9
+
+// DigitPrimes returns the primes with exactly the given number of decimal
+// digits, that is the primes in [10^(digits-1),10^digits), in increasing
+// order; for example, DigitPrimes(2) returns the 21 primes from 11 to 97.
+// The primes are found with SieveRange. Their number grows tenfold with each
+// digit (there are over 45 million primes with 9 digits), so the number of
+// digits is capped at maxDigitPrimesDigits.
+// If digits is less than 1 or greater than maxDigitPrimesDigits, it returns
+// an empty list.
+func DigitPrimes(digits int) []int {
+	if digits < 1 || digits > maxDigitPrimesDigits {
+		return []int{}
+	}
+	lo := 1
+	for i := 1; i < digits; i++ {
+		lo *= 10
+	}
+	return SieveRange(lo, 10*lo-1)
+}
+
+// IsSuperPrime returns true if n is a super-prime, that is a prime whose
+// index in the sequence of primes is itself prime; for example, 5 is a
+// super-prime since it is the 3rd prime, while 7, the 4th prime, is not.
+// The index of n is computed with PiExact, so it takes O(n log log n) time
+// for n beyond the range of cached primes.
+// See https://en.wikipedia.org/wiki/Super-prime for details.
+func IsSuperPrime(n int) bool {
+	return IsPrime(n) && IsPrime(PiExact(n))
+}
+
+// AntiDivisors returns the anti-divisors of n in increasing order;
+// for example, AntiDivisors(10) returns [3 4 7].
+// An anti-divisor of n is a number k in (1,n) that does not divide n but
+// comes as close as possible to doing so: either k is odd and divides
+// 2n-1 or 2n+1, or k is even and n mod k == k/2 (i.e. k divides 2n but not
+// n).
+// Rather than trying every k, AntiDivisors derives the odd anti-divisors
+// from the divisors of 2n-1 and 2n+1 and the even ones from the odd
+// divisors of n, so 2n+1 must fit in an int.
+// If n is less than 3, it returns an empty list.
+// See https://oeis.org/A066272 for details.
+func AntiDivisors(n int) []int {
+	ks := []int{}
+	if n < 3 {
+		return ks
+	}
+	// k even with n mod k == k/2 means n == (k/2)*e for some odd e >= 3
+	for _, e := range Divisors(n) {
+		if e > 1 && e%2 == 1 {
+			ks = append(ks, 2*n/e)
+		}
+	}
+	// Each divisor of the odd numbers 2n-1 and 2n+1 is odd and cannot
+	// divide n as well
+	for _, m := range []int{2*n - 1, 2*n + 1} {
+		for _, k := range Divisors(m) {
+			if k > 1 && k < n {
+				ks = append(ks, k)
+			}
+		}
+	}
+	sort.Ints(ks)
+	return ks
+}
+
+// InsertablePrimes returns the primes p <= n, in increasing order, such that
+// inserting the decimal digit d somewhere in p (before its first digit,
+// between two digits, or after its last digit) gives another prime; for
+// example, 3 is in InsertablePrimes(10,1) because 13 is prime.
+// Putting a 0 in front of p does not count, as it leaves p unchanged.
+// The search is exhaustive: for each of the Pi(n) primes p with k digits,
+// it tries the k+1 numbers obtained by inserting d and tests each of them
+// with IsPrime, which takes O(sqrt(n)) time at worst.
+// Insertions that would overflow an int are skipped.
+// If d is not a decimal digit, it returns an empty list.
+func InsertablePrimes(n, d int) []int {
+	ps := []int{}
+	if d < 0 || d > 9 {
+		return ps
+	}
+	for _, p := range Sieve(n) {
+		if p > (math.MaxInt-9)/10 {
+			break
+		}
+		// Insert d at each position, splitting p into a high part and a
+		// low part with pow10 > low
+		for pow10 := 1; ; pow10 *= 10 {
+			high, low := p/pow10, p%pow10
+			if high > 0 || d > 0 {
+				if IsPrime((high*10+d)*pow10 + low) {
+					ps = append(ps, p)
+					break
+				}
+			}
+			if high == 0 {
+				break
+			}
+		}
+	}
+	return ps
+}
+
+// PolyPrimeCount returns the number of integers n in [from,to] for which
+// the polynomial with the given coefficients takes a prime value.
+// The coefficients are listed from the highest degree down, as the
+// polynomial is usually written, and the polynomial is evaluated with
+// Horner's rule; for example, Euler's polynomial n^2+n+41 is []int{1,1,41}
+// and PolyPrimeCount([]int{1,1,41},0,39) == 40, since it is prime for every
+// n in [0,39].
+// Negative values are never counted as primes and each value is tested
+// with IsPrime. The caller must make sure the values fit in an int.
+// If from > to, it returns 0.
+// See https://en.wikipedia.org/wiki/Formula_for_primes#Prime_formulas_and_polynomial_functions
+// for details.
+func PolyPrimeCount(coeffs []int, from, to int) int {
+	count := 0
+	for n := from; n <= to; n++ {
+		v := 0
+		for _, c := range coeffs {
+			v = v*n + c
+		}
+		if IsPrime(v) {
+			count++
+		}
+		if n == to {
+			// Avoid overflowing n when to == math.MaxInt
+			break
+		}
+	}
+	return count
+}
+
+// reverseDigits returns the number whose decimal digits are those of n
+// in reverse order, for n >= 0; for example, reverseDigits(1230) == 321.
+// If the result does not fit in an int, ok is false.
+func reverseDigits(n int) (r int, ok bool) {
+	for ; n > 0; n /= 10 {
+		if r > (math.MaxInt-n%10)/10 {
+			return 0, false
+		}
+		r = r*10 + n%10
+	}
+	return r, true
+}
+
+// IsEmirp returns true if n is an emirp, that is a prime whose decimal
+// digits reversed give a different prime; for example, 13 is an emirp
+// since 31 is prime, while the palindromic prime 11 is not.
+// Both n and its reversal are tested with IsPrime.
+// See https://en.wikipedia.org/wiki/Emirp for details.
+func IsEmirp(n int) bool {
+	if !IsPrime(n) {
+		return false
+	}
+	r, ok := reverseDigits(n)
+	return ok && r != n && IsPrime(r)
+}
+
+// Emirps returns the emirps less than or equal to limit in increasing
+// order (see IsEmirp); for example, Emirps(100) returns
+// [13 17 31 37 71 73 79 97].
+// The primes are generated by Sieve(limit) and only their reversals are
+// tested with IsPrime, since they may well exceed limit.
+// See https://oeis.org/A006567 for details.
+func Emirps(limit int) []int {
+	es := []int{}
+	for _, p := range Sieve(limit) {
+		if r, ok := reverseDigits(p); ok && r != p && IsPrime(r) {
+			es = append(es, p)
+		}
+	}
+	return es
+}
+
+// PalindromicPrimes returns the primes less than or equal to limit whose
+// decimal representation reads the same forwards and backwards, in
+// increasing order; for example, PalindromicPrimes(200) returns
+// [2 3 5 7 11 101 131 151 181 191].
+// Rather than testing every prime, it builds the palindromes directly by
+// mirroring their leading half and tests each of them with IsPrime.
+// Palindromes with an even number of digits are all multiples of 11, so 11
+// is the only prime among them and only the ones with an odd number of
+// digits are built; those ending in an even digit or in 5 are skipped too.
+// See https://en.wikipedia.org/wiki/Palindromic_prime for details.
+func PalindromicPrimes(limit int) []int {
+	ps := []int{}
+	// Each pass builds the palindromes with 2*k-1 digits from their
+	// leading k digits x, as x*10^(k-1) followed by the reversal of x/10
+	for lo := 1; ; lo *= 10 {
+		for x := lo; x < 10*lo; x++ {
+			if first := x / lo; lo > 1 && (first%2 == 0 || first == 5) {
+				// Skip to the next leading digit
+				x += lo - 1
+				continue
+			}
+			if x > (math.MaxInt-lo)/lo {
+				return ps
+			}
+			r, _ := reverseDigits(x / 10)
+			p := x*lo + r
+			if p > limit {
+				return ps
+			}
+			if IsPrime(p) {
+				ps = append(ps, p)
+			}
+		}
+		if lo == 1 && limit >= 11 {
+			ps = append(ps, 11)
+		}
+	}
+}
+
+// IsCircularPrime returns true if n is a circular prime, that is a prime
+// such that every rotation of its decimal digits is prime too; for
+// example, 197 is a circular prime since 971 and 719 are also prime.
+// Each rotation is tested with IsPrime, stopping at the first composite.
+// See https://en.wikipedia.org/wiki/Circular_prime for details.
+func IsCircularPrime(n int) bool {
+	if !IsPrime(n) {
+		return false
+	}
+	// pow10 is the value of the leading digit position of n
+	pow10 := 1
+	for pow10 <= n/10 {
+		pow10 *= 10
+	}
+	// Move the last digit to the front until n comes around again; a 0
+	// digit ends up as the last one in some rotation, which is then caught
+	// as composite
+	for r := n; ; {
+		d := r % 10
+		if d > (math.MaxInt-r/10)/pow10 {
+			// The rotation does not fit in an int
+			return false
+		}
+		if r = d*pow10 + r/10; r == n {
+			return true
+		}
+		if !IsPrime(r) {
+			return false
+		}
+	}
+}
+
+// CircularPrimes returns the circular primes less than or equal to limit
+// in increasing order (see IsCircularPrime); for example,
+// CircularPrimes(100) returns the 13 primes
+// [2 3 5 7 11 13 17 31 37 71 73 79 97].
+// Apart from 2 and 5, a circular prime can only have the digits 1, 3, 7,
+// and 9, since any other digit is the last one in some rotation, so the
+// primes generated by Sieve(limit) are screened on their digits before
+// their rotations are tested.
+// See https://oeis.org/A068652 for details.
+func CircularPrimes(limit int) []int {
+	cs := []int{}
+primes:
+	for _, p := range Sieve(limit) {
+		if p > 10 {
+			for m := p; m > 0; m /= 10 {
+				if d := m % 10; d%2 == 0 || d == 5 {
+					continue primes
+				}
+			}
+		}
+		if IsCircularPrime(p) {
+			cs = append(cs, p)
+		}
+	}
+	return cs
+}
@@ -0,0 +1,438 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestLuckyPrimes(t *testing.T) {
+	cases := []struct {
+		n    int
+		want []int
+	}{
+		{-1, []int{}},
+		{0, []int{}},
+		{2, []int{}},
+		{3, []int{3}},
+		{100, []int{3, 7, 13, 31, 37, 43, 67, 73, 79}},
+		// See https://oeis.org/A031157
+		{500, []int{
+			3, 7, 13, 31, 37, 43, 67, 73, 79, 127, 151, 163, 193, 211, 223,
+			241, 283, 307, 331, 349, 367, 409, 421, 433, 463, 487,
+		}},
+	}
+	for _, c := range cases {
+		lps := primes.LuckyPrimes(c.n)
+		if !reflect.DeepEqual(lps, c.want) {
+			t.Errorf("LuckyPrimes(%d) == %v, want %v", c.n, lps, c.want)
+		}
+	}
+
+	// Check a longer list against a plain implementation of the sieve
+	const n = 20000
+	ls := []int{}
+	for k := 1; k <= n; k += 2 {
+		ls = append(ls, k)
+	}
+	for i := 1; i < len(ls) && ls[i] <= len(ls); i++ {
+		var survivors []int
+		for m, l := range ls {
+			if (m+1)%ls[i] != 0 {
+				survivors = append(survivors, l)
+			}
+		}
+		ls = survivors
+	}
+	want := []int{}
+	for _, l := range ls {
+		if primes.IsPrime(l) {
+			want = append(want, l)
+		}
+	}
+	if lps := primes.LuckyPrimes(n); !reflect.DeepEqual(lps, want) {
+		t.Errorf("LuckyPrimes(%d) == %v, want %v", n, lps, want)
+	}
+}
+
+func TestDigitPrimes(t *testing.T) {
+	cases := []struct {
+		digits int
+		want   []int
+	}{
+		{-1, []int{}},
+		{0, []int{}},
+		{1, []int{2, 3, 5, 7}},
+		{10, []int{}},
+	}
+	for _, c := range cases {
+		if ps := primes.DigitPrimes(c.digits); !reflect.DeepEqual(ps, c.want) {
+			t.Errorf("DigitPrimes(%d) == %v, want %v", c.digits, ps, c.want)
+		}
+	}
+
+	// See https://oeis.org/A006879 for the counts
+	counts := []int{4, 21, 143, 1061, 8363, 68906, 586081}
+	lo := 1
+	for i, count := range counts {
+		digits := i + 1
+		ps := primes.DigitPrimes(digits)
+		if len(ps) != count {
+			t.Errorf("DigitPrimes(%d) returned %d primes, want %d", digits, len(ps), count)
+			continue
+		}
+		if first, last := ps[0], ps[len(ps)-1]; first < lo || last >= 10*lo ||
+			primes.NextPrimeInclusive(lo) != first || primes.NextPrimeInclusive(last+1) < 10*lo {
+			t.Errorf("DigitPrimes(%d) ranges from %d to %d", digits, first, last)
+		}
+		lo *= 10
+	}
+	if ps := primes.DigitPrimes(2); ps[0] != 11 || ps[len(ps)-1] != 97 {
+		t.Errorf("DigitPrimes(2) ranges from %d to %d, want 11 to 97", ps[0], ps[len(ps)-1])
+	}
+}
+
+func TestIsSuperPrime(t *testing.T) {
+	// See https://oeis.org/A006450
+	want := []int{
+		3, 5, 11, 17, 31, 41, 59, 67, 83, 109, 127, 157, 179, 191, 211,
+		241, 277, 283, 331, 353, 367, 401, 431, 461, 509, 547, 563, 587,
+	}
+	sps := []int{}
+	for n := -10; n <= 590; n++ {
+		if primes.IsSuperPrime(n) {
+			sps = append(sps, n)
+		}
+	}
+	if !reflect.DeepEqual(sps, want) {
+		t.Errorf("IsSuperPrime is true for %v, want %v", sps, want)
+	}
+
+	// The super-primes are the primes at prime positions
+	ps := primes.Sieve(30000)
+	for i, p := range ps {
+		if sp, want := primes.IsSuperPrime(p), primes.IsPrime(i+1); sp != want {
+			t.Errorf("IsSuperPrime(%d) == %v, want %v", p, sp, want)
+		}
+	}
+}
+
+func TestAntiDivisors(t *testing.T) {
+	cases := []struct {
+		n    int
+		want []int
+	}{
+		{-1, []int{}},
+		{0, []int{}},
+		{1, []int{}},
+		{2, []int{}},
+		{3, []int{2}},
+		{5, []int{2, 3}},
+		{10, []int{3, 4, 7}},
+		// See https://oeis.org/A066272
+		{17, []int{2, 3, 5, 7, 11}},
+		{30, []int{4, 12, 20}},
+	}
+	for _, c := range cases {
+		ks := primes.AntiDivisors(c.n)
+		if !reflect.DeepEqual(ks, c.want) {
+			t.Errorf("AntiDivisors(%d) == %v, want %v", c.n, ks, c.want)
+		}
+	}
+
+	// Check against the definition for small n
+	for n := 3; n <= 1000; n++ {
+		want := []int{}
+		for k := 2; k < n; k++ {
+			if k%2 == 1 && ((2*n-1)%k == 0 || (2*n+1)%k == 0) ||
+				k%2 == 0 && n%k == k/2 {
+				want = append(want, k)
+			}
+		}
+		if ks := primes.AntiDivisors(n); !reflect.DeepEqual(ks, want) {
+			t.Errorf("AntiDivisors(%d) == %v, want %v", n, ks, want)
+		}
+	}
+}
+
+func TestInsertablePrimes(t *testing.T) {
+	cases := []struct {
+		n, d int
+		want []int
+	}{
+		{-1, 1, []int{}},
+		{100, -1, []int{}},
+		{100, 10, []int{}},
+		{10, 1, []int{3, 7}},
+		{30, 0, []int{11, 13, 17, 19}},
+	}
+	for _, c := range cases {
+		ps := primes.InsertablePrimes(c.n, c.d)
+		if !reflect.DeepEqual(ps, c.want) {
+			t.Errorf("InsertablePrimes(%d,%d) == %v, want %v", c.n, c.d, ps, c.want)
+		}
+	}
+
+	// Check against insertions done on the decimal strings
+	const n = 3000
+	for d := 0; d <= 9; d++ {
+		want := []int{}
+		for _, p := range primes.Sieve(n) {
+			s := strconv.Itoa(p)
+			for i := 0; i <= len(s); i++ {
+				if i == 0 && d == 0 {
+					continue
+				}
+				q, _ := strconv.Atoi(s[:i] + strconv.Itoa(d) + s[i:])
+				if primes.IsPrime(q) {
+					want = append(want, p)
+					break
+				}
+			}
+		}
+		ps := primes.InsertablePrimes(n, d)
+		if !reflect.DeepEqual(ps, want) {
+			t.Errorf("InsertablePrimes(%d,%d) == %v, want %v", n, d, ps, want)
+		}
+		// Every result is a prime itself
+		for _, p := range ps {
+			if !primes.IsPrime(p) {
+				t.Errorf("InsertablePrimes(%d,%d) returned composite %d", n, d, p)
+			}
+		}
+	}
+}
+
+func TestPolyPrimeCount(t *testing.T) {
+	euler := []int{1, 1, 41}
+	cases := []struct {
+		coeffs   []int
+		from, to int
+		want     int
+	}{
+		{euler, 0, 39, 40},
+		{euler, 0, 40, 40},
+		{euler, 40, 41, 0},
+		{euler, 10, 0, 0},
+		{nil, 0, 10, 0},
+		{[]int{7}, 0, 9, 10},
+		{[]int{1, 0}, -100, 100, 25},
+		{[]int{-1, 0}, -100, 100, 25},
+		// Legendre's 2n^2+29 is prime for n in [0,28]
+		{[]int{2, 0, 29}, 0, 28, 29},
+		// n^2-79n+1601 is prime for n in [0,79], with each prime taken twice
+		{[]int{1, -79, 1601}, 0, 79, 80},
+	}
+	for _, c := range cases {
+		if count := primes.PolyPrimeCount(c.coeffs, c.from, c.to); count != c.want {
+			t.Errorf("PolyPrimeCount(%v,%d,%d) == %d, want %d", c.coeffs, c.from, c.to, count, c.want)
+		}
+	}
+
+	// Check a cubic against a plain evaluation of the polynomial
+	want := 0
+	for n := -500; n <= 500; n++ {
+		if primes.IsPrime(2*n*n*n - 3*n + 5) {
+			want++
+		}
+	}
+	if count := primes.PolyPrimeCount([]int{2, 0, -3, 5}, -500, 500); count != want {
+		t.Errorf("PolyPrimeCount([2 0 -3 5],-500,500) == %d, want %d", count, want)
+	}
+}
+
+func TestIsEmirp(t *testing.T) {
+	cases := []struct {
+		n    int
+		want bool
+	}{
+		{-13, false},
+		{0, false},
+		{2, false},
+		{11, false},
+		{13, true},
+		{17, true},
+		{23, false},
+		{31, true},
+		{79, true},
+		{101, false},
+		{107, true},
+		{1009, true},
+	}
+	for _, c := range cases {
+		if e := primes.IsEmirp(c.n); e != c.want {
+			t.Errorf("IsEmirp(%d) == %v, want %v", c.n, e, c.want)
+		}
+	}
+
+	// See https://oeis.org/A006567
+	want := []int{13, 17, 31, 37, 71, 73, 79, 97}
+	if es := primes.Emirps(100); !reflect.DeepEqual(es, want) {
+		t.Errorf("Emirps(100) == %v, want %v", es, want)
+	}
+	if es := primes.Emirps(12); len(es) != 0 {
+		t.Errorf("Emirps(12) == %v, want []", es)
+	}
+
+	// Check against the reversals of the decimal representations
+	es := primes.Emirps(100000)
+	i := 0
+	for n := 0; n <= 100000; n++ {
+		s := []byte(strconv.Itoa(n))
+		for l, r := 0, len(s)-1; l < r; l, r = l+1, r-1 {
+			s[l], s[r] = s[r], s[l]
+		}
+		m, _ := strconv.Atoi(string(s))
+		want := primes.IsPrime(n) && primes.IsPrime(m) && m != n
+		if e := primes.IsEmirp(n); e != want {
+			t.Errorf("IsEmirp(%d) == %v, want %v", n, e, want)
+		}
+		if want {
+			if i >= len(es) || es[i] != n {
+				t.Errorf("Emirps(100000) is missing %d", n)
+				continue
+			}
+			i++
+		}
+	}
+	if i != len(es) {
+		t.Errorf("Emirps(100000) has %d emirps, want %d", len(es), i)
+	}
+}
+
+func TestPalindromicPrimes(t *testing.T) {
+	cases := []struct {
+		limit int
+		want  []int
+	}{
+		{-1, []int{}},
+		{1, []int{}},
+		{2, []int{2}},
+		{10, []int{2, 3, 5, 7}},
+		{11, []int{2, 3, 5, 7, 11}},
+		{100, []int{2, 3, 5, 7, 11}},
+		{200, []int{2, 3, 5, 7, 11, 101, 131, 151, 181, 191}},
+	}
+	for _, c := range cases {
+		if ps := primes.PalindromicPrimes(c.limit); !reflect.DeepEqual(ps, c.want) {
+			t.Errorf("PalindromicPrimes(%d) == %v, want %v", c.limit, ps, c.want)
+		}
+	}
+
+	// See https://oeis.org/A002385 and https://oeis.org/A050251
+	counts := []struct {
+		limit int
+		want  int
+	}{
+		{1000, 20},
+		{100000, 113},
+		{10000000, 781},
+	}
+	for _, c := range counts {
+		if ps := primes.PalindromicPrimes(c.limit); len(ps) != c.want {
+			t.Errorf("len(PalindromicPrimes(%d)) == %d, want %d", c.limit, len(ps), c.want)
+		}
+	}
+
+	// Check against the palindromes among the primes generated by Sieve
+	want := []int{}
+	for _, p := range primes.Sieve(2000000) {
+		s := strconv.Itoa(p)
+		palindrome := true
+		for l, r := 0, len(s)-1; l < r; l, r = l+1, r-1 {
+			palindrome = palindrome && s[l] == s[r]
+		}
+		if palindrome {
+			want = append(want, p)
+		}
+	}
+	if ps := primes.PalindromicPrimes(2000000); !reflect.DeepEqual(ps, want) {
+		t.Errorf("PalindromicPrimes(2000000) == %v, want %v", ps, want)
+	}
+}
+
+func TestIsCircularPrime(t *testing.T) {
+	cases := []struct {
+		n    int
+		want bool
+	}{
+		{-2, false},
+		{0, false},
+		{1, false},
+		{2, true},
+		{11, true},
+		{19, false},
+		{23, false},
+		{101, false},
+		{197, true},
+		{199, true},
+		{919, true},
+		{1193, true},
+		{1931, true},
+		{1933, false},
+		{999331, true},
+	}
+	for _, c := range cases {
+		if p := primes.IsCircularPrime(c.n); p != c.want {
+			t.Errorf("IsCircularPrime(%d) == %v, want %v", c.n, p, c.want)
+		}
+	}
+
+	// See https://oeis.org/A068652
+	want := []int{2, 3, 5, 7, 11, 13, 17, 31, 37, 71, 73, 79, 97}
+	if cs := primes.CircularPrimes(100); !reflect.DeepEqual(cs, want) {
+		t.Errorf("CircularPrimes(100) == %v, want %v", cs, want)
+	}
+	if cs := primes.CircularPrimes(1000000); len(cs) != 55 {
+		t.Errorf("len(CircularPrimes(1000000)) == %d, want 55", len(cs))
+	}
+
+	// Check against the rotations of the decimal representations
+	cs := primes.CircularPrimes(200000)
+	i := 0
+	for n := 0; n <= 200000; n++ {
+		s := strconv.Itoa(n)
+		want := true
+		for k := range s {
+			m, _ := strconv.Atoi(s[k:] + s[:k])
+			want = want && primes.IsPrime(m)
+		}
+		if p := primes.IsCircularPrime(n); p != want {
+			t.Errorf("IsCircularPrime(%d) == %v, want %v", n, p, want)
+		}
+		if want {
+			if i >= len(cs) || cs[i] != n {
+				t.Errorf("CircularPrimes(200000) is missing %d", n)
+				continue
+			}
+			i++
+		}
+	}
+	if i != len(cs) {
+		t.Errorf("CircularPrimes(200000) has %d primes, want %d", len(cs), i)
+	}
+}
@@ -0,0 +1,425 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+import (
+	"math"
+	"math/bits"
+)
+
+// segmentSize is the number of odd candidates sieved at a time by
+// sieveSegments; it is chosen so that a segment fits comfortably in cache.
+const segmentSize = 1 << 15
+
+// isqrt returns the largest integer r such that r*r <= n, for any n >= 0;
+// it returns 0 if n is negative.
+func isqrt(n int) int {
+	return int(isqrt64(int64(n)))
+}
+
+// isqrt64 is like isqrt, but for int64 values.
+func isqrt64(n int64) int64 {
+	if n <= 0 {
+		return 0
+	}
+	r := int64(math.Sqrt(float64(n)))
+	// Correct any rounding error from the floating point square root
+	for r > 0 && r > n/r {
+		r--
+	}
+	for r+1 <= n/(r+1) {
+		r++
+	}
+	return r
+}
+
+// sieveSegments runs a segmented sieve of Eratosthenes over the odd numbers
+// in [lo,hi], marking off their multiples of the odd primes up to sqrt(hi).
+// For each segment, in increasing order, it calls f(base,a) where base is an
+// odd number and a[i] == false if and only if base+2*i is prime; the prime 2
+// is never reported since base is always at least 3.
+// It stops as soon as f returns false.
+// The slice a is reused from one segment to the next, so sieveSegments
+// takes O(sqrt(hi)) memory regardless of the width of the range.
+// It works with int64 values so that it can serve both the int and the
+// int64 versions of the functions built on it.
+// See https://en.wikipedia.org/wiki/Sieve_of_Eratosthenes#Segmented_sieve
+// for details.
+func sieveSegments(lo, hi int64, f func(base int64, a []bool) bool) {
+	if lo < 3 {
+		lo = 3
+	}
+	if lo%2 == 0 {
+		lo++
+	}
+	if lo > hi {
+		return
+	}
+	// The odd primes up to sqrt(hi) are enough to mark off all the
+	// composite numbers in [lo,hi]
+	sieveSegmentsWith(oddPrimesUpTo(isqrt64(hi)), lo, hi, f)
+}
+
+// oddPrimesUpTo returns a list of the odd primes less than or equal to n.
+// The primes are kept as int64 values so that the base primes of a sieve
+// over any int64 range are available even where an int has only 32 bits.
+func oddPrimesUpTo(n int64) []int64 {
+	ps := []int64{}
+	if int64(int(n)) != n {
+		// n does not fit in an int; sieve the primes in segments instead
+		sieveSegments(3, n, func(base int64, a []bool) bool {
+			for i, composite := range a {
+				if !composite {
+					ps = append(ps, base+2*int64(i))
+				}
+			}
+			return true
+		})
+		return ps
+	}
+	for _, p := range Sieve(int(n)) {
+		if p > 2 {
+			ps = append(ps, int64(p))
+		}
+	}
+	return ps
+}
+
+// sieveSegmentsWith is like sieveSegments, but it uses the given list of
+// odd primes to mark off the composite numbers, so that the list can be
+// shared by several calls; the list must include all the odd primes up to
+// sqrt(hi) and lo must be odd and at least 3.
+func sieveSegmentsWith(ps []int64, lo, hi int64, f func(base int64, a []bool) bool) {
+	if lo > hi {
+		return
+	}
+	buf := make([]bool, segmentSize)
+	for base := lo; ; base += 2 * segmentSize {
+		length := int64(segmentSize)
+		if l := (hi-base)/2 + 1; l < length {
+			length = l
+		}
+		a := buf[:length]
+		for i := range a {
+			a[i] = false
+		}
+		last := base + 2*(length-1)
+		for _, p := range ps {
+			if p > last/p {
+				break
+			}
+			// Find the offset from base of the first odd multiple of p
+			// that needs to be marked off (no smaller than p*p)
+			var off int64
+			if pp := p * p; pp >= base {
+				off = pp - base
+			} else if off = (p - base%p) % p; off%2 == 1 {
+				off += p
+			}
+			for j := off / 2; j < length; j += p {
+				a[j] = true
+			}
+		}
+		if !f(base, a) || hi-last < 2 {
+			return
+		}
+	}
+}
+
+// PiExact returns the number of primes less than or equal to n.
+// Unlike Pi, it always returns an exact count: if n is outside the range
+// covered by the cache, it counts the primes with a segmented sieve of
+// Eratosthenes which does not store them and takes only O(sqrt(n)) memory.
+// PiExact takes O(n log log n) time, so it is practical for n up to 10^9
+// or so.
+func PiExact(n int) int {
+	if pi, ok := Pi(n); ok {
+		return pi
+	}
+	// Count the prime 2 and then all the odd primes
+	pi := 1
+	sieveSegments(3, int64(n), func(base int64, a []bool) bool {
+		for _, composite := range a {
+			if !composite {
+				pi++
+			}
+		}
+		return true
+	})
+	return pi
+}
+
+// PiRange returns the number of primes p such that lo <= p <= hi.
+// It counts the primes with a segmented sieve of Eratosthenes over [lo,hi],
+// so it can count the primes in a narrow range of large numbers without
+// having to sieve all the numbers below lo.
+// If lo > hi, it returns 0.
+func PiRange(lo, hi int) int {
+	pi := 0
+	if lo <= 2 && 2 <= hi {
+		pi++
+	}
+	sieveSegments(int64(lo), int64(hi), func(base int64, a []bool) bool {
+		for _, composite := range a {
+			if !composite {
+				pi++
+			}
+		}
+		return true
+	})
+	return pi
+}
+
+// CountRange returns the number of primes p such that lo <= p < hi.
+// It is equivalent to PiRange(lo,hi-1): the primes are counted with a
+// segmented sieve without being stored, so it takes little memory even
+// for a wide range.
+// Negative values of lo count from 0 and if lo >= hi, it returns 0.
+func CountRange(lo, hi int) int {
+	if lo < 0 {
+		lo = 0
+	}
+	if lo >= hi {
+		return 0
+	}
+	return PiRange(lo, hi-1)
+}
+
+// SumRange returns the sum of the primes p such that lo <= p <= hi.
+// Like PiRange, it adds up the primes with a segmented sieve of
+// Eratosthenes over [lo,hi], so it can sum the primes in a narrow range of
+// large numbers without having to sieve all the numbers below lo.
+// The sum is returned as an int64 so that it cannot overflow even where an
+// int has only 32 bits: the sum of the primes up to n grows like
+// n^2/(2*log(n)), so it fits in an int64 for any range below 2*10^10.
+// If the range contains no primes, it returns 0.
+func SumRange(lo, hi int) int64 {
+	var sum int64
+	if lo <= 2 && 2 <= hi {
+		sum = 2
+	}
+	sieveSegments(int64(lo), int64(hi), func(base int64, a []bool) bool {
+		for i, composite := range a {
+			if !composite {
+				sum += base + 2*int64(i)
+			}
+		}
+		return true
+	})
+	return sum
+}
+
+// CountPrimesInAP returns the number of primes p <= n such that
+// p == r (mod d), that is the number of primes up to n in the arithmetic
+// progression r, r+d, r+2d, ...; for example,
+// CountPrimesInAP(1,4,100) == 11.
+// If r and d are coprime, Dirichlet's theorem states that the progression
+// contains infinitely many primes and that, as n grows, the primes up to n
+// split evenly among the EulerPhi(d) residues coprime to d; the primes are
+// then counted with a segmented sieve of Eratosthenes, which does not store
+// them and takes only O(sqrt(n)) memory.
+// If r and d are not coprime, any prime p in the progression is divisible
+// by g == GCD(r,d), so the only candidate is p == g itself and
+// CountPrimesInAP returns 1 if g is a prime <= n in the progression and 0
+// otherwise.
+// If d is less than 1, it returns 0.
+// See https://en.wikipedia.org/wiki/Dirichlet%27s_theorem_on_arithmetic_progressions
+// for details.
+func CountPrimesInAP(r, d, n int) int {
+	if d < 1 {
+		return 0
+	}
+	if r %= d; r < 0 {
+		r += d
+	}
+	if g := GCD(r, d); g != 1 {
+		if g <= n && g%d == r && IsPrime(g) {
+			return 1
+		}
+		return 0
+	}
+	count := 0
+	if n >= 2 && 2%d == r {
+		count++
+	}
+	d64, r64 := int64(d), int64(r)
+	sieveSegments(3, int64(n), func(base int64, a []bool) bool {
+		for i, composite := range a {
+			if !composite && (base+2*int64(i))%d64 == r64 {
+				count++
+			}
+		}
+		return true
+	})
+	return count
+}
+
+// PrimeIndexRange returns the k-th prime p_k, counting from p_1 == 2,
+// together with the next prime p_(k+1), so that nextPrime-prime is the gap
+// that follows p_k; it inverts PiExact, in that PiExact(n) == k for any n
+// in [prime,nextPrime).
+// If both primes are in the cache, they are simply looked up; otherwise,
+// they are found by counting the primes with a segmented sieve up to
+// Rosser's upper bound for p_(k+1), that is n*(log(n)+log(log(n))) for
+// n == k+1, which takes O(sqrt(p_k)) memory and O(p_k log log p_k) time.
+// If k is less than 1, or if a prime does not fit in an int, the
+// corresponding result is 0.
+// See https://en.wikipedia.org/wiki/Prime_number_theorem#Approximations_for_the_nth_prime_number
+// for details.
+func PrimeIndexRange(k int) (prime, nextPrime int) {
+	if k < 1 {
+		return 0, 0
+	}
+	if primes, _ := cachedPrimes(); k < len(primes) {
+		return primes[k-1], primes[k]
+	}
+	x := float64(k) + 1
+	hi := int64(math.MaxInt)
+	if b := x*(math.Log(x)+math.Log(math.Log(x))) + 1; b < float64(math.MaxInt) {
+		hi = int64(b)
+	}
+	// Count the prime 2 and then the odd primes until p_(k+1)
+	pi := 1
+	sieveSegments(3, hi, func(base int64, a []bool) bool {
+		for i, composite := range a {
+			if !composite {
+				pi++
+				switch pi {
+				case k:
+					prime = int(base) + 2*i
+				case k + 1:
+					nextPrime = int(base) + 2*i
+					return false
+				}
+			}
+		}
+		return true
+	})
+	return prime, nextPrime
+}
+
+// PrimesPerBitLength returns a list whose element i holds the number of
+// primes with exactly b == i+2 bits, that is the number of primes in
+// [2^(b-1),2^b), for b in [2,maxBits].
+// Since the density of the primes around n is about 1/log(n), each count is
+// a little less than twice the previous one.
+// The counts are computed with PiRange, which takes O(2^b) time for b bits,
+// so maxBits should be kept small (it is capped to the size of an int).
+// If maxBits is less than 2, it returns an empty list.
+func PrimesPerBitLength(maxBits int) []int {
+	if maxBits > bits.UintSize-1 {
+		maxBits = bits.UintSize - 1
+	}
+	counts := []int{}
+	for b := 2; b <= maxBits; b++ {
+		lo := 1 << uint(b-1)
+		counts = append(counts, PiRange(lo, lo+(lo-1)))
+	}
+	return counts
+}
+
+// SieveRange returns a list of the prime numbers p such that lo <= p <= hi.
+// If there are no such primes, it returns an empty list.
+// It uses a segmented sieve of Eratosthenes over [lo,hi], which takes
+// O(sqrt(hi)) memory beyond that needed for the result, so it can find
+// the primes in a narrow range of large numbers without having to sieve all
+// the numbers below lo.
+func SieveRange(lo, hi int) []int {
+	ps := []int{}
+	if lo <= 2 && 2 <= hi {
+		ps = append(ps, 2)
+	}
+	sieveSegments(int64(lo), int64(hi), func(base int64, a []bool) bool {
+		for i, composite := range a {
+			if !composite {
+				ps = append(ps, int(base)+2*i)
+			}
+		}
+		return true
+	})
+	return ps
+}
+
+// Sieve64 is like Sieve, but it takes and returns int64 values, so that it
+// can be used with the full int64 range regardless of the size of an int.
+// It is equivalent to SieveRange64(2,n).
+func Sieve64(n int64) []int64 {
+	return SieveRange64(2, n)
+}
+
+// SieveRange64 is like SieveRange, but it takes and returns int64 values,
+// so that it can be used with the full int64 range regardless of the size
+// of an int.
+// Like SieveRange, it keeps the primes up to sqrt(hi) in memory, so a range
+// near the top of the int64 range needs a few gigabytes and takes minutes
+// just to find them, however narrow the range.
+func SieveRange64(lo, hi int64) []int64 {
+	ps := []int64{}
+	if lo <= 2 && 2 <= hi {
+		ps = append(ps, 2)
+	}
+	sieveSegments(lo, hi, func(base int64, a []bool) bool {
+		for i, composite := range a {
+			if !composite {
+				ps = append(ps, base+2*int64(i))
+			}
+		}
+		return true
+	})
+	return ps
+}
+
+// SmallestPrimeFactorsSegment returns a list whose element i holds the
+// smallest prime factor of lo+i, for each integer in [lo,hi); the entries
+// for the integers less than 2, which have no prime factors, are 0.
+// It works like a segmented sieve of Eratosthenes: each prime p up to
+// sqrt(hi) marks the multiples of p in [lo,hi) that no smaller prime has
+// marked yet, and the entries still unmarked at the end are primes.
+// Since only the table for [lo,hi) is kept, it takes O(hi-lo+sqrt(hi))
+// memory, so numbers in a narrow range of large values can be factored
+// without a table of smallest prime factors starting from 0.
+// If lo >= hi, it returns an empty list.
+func SmallestPrimeFactorsSegment(lo, hi int) []int {
+	if lo >= hi {
+		return []int{}
+	}
+	spf := make([]int, hi-lo)
+	for _, p := range Sieve(isqrt(hi - 1)) {
+		// Multiples of p below p*p have a smaller prime factor
+		m := p * p
+		if m < lo {
+			m = lo + (p-lo%p)%p
+		}
+		for ; m < hi; m += p {
+			if spf[m-lo] == 0 {
+				spf[m-lo] = p
+			}
+		}
+	}
+	for i := range spf {
+		if n := lo + i; spf[i] == 0 && n >= 2 {
+			spf[i] = n
+		}
+	}
+	return spf
+}
@@ -0,0 +1,398 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"math"
+	"math/bits"
+	"reflect"
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestPiExact(t *testing.T) {
+	cases := []struct {
+		n    int
+		want int
+	}{
+		{-1, 0},
+		{0, 0},
+		{1, 0},
+		{2, 1},
+		{3, 2},
+		{10, 4},
+		{100, 25},
+		{1000, 168},
+		{10000, 1229},
+		{10007, 1230},
+		{100000, 9592},
+		{104729, 10000},
+		{104730, 10000},
+		{1000000, 78498},
+		{10000000, 664579},
+		{100000000, 5761455},
+		{1000000000, 50847534},
+	}
+	for _, c := range cases {
+		if testing.Short() && c.n > 10000000 {
+			continue
+		}
+		if pi := primes.PiExact(c.n); pi != c.want {
+			t.Errorf("PiExact(%d) == %d, want %d", c.n, pi, c.want)
+		}
+	}
+
+	// Check against the number of primes generated by Sieve
+	for n := 9900; n < 140000; n += 997 {
+		if pi, want := primes.PiExact(n), len(primes.Sieve(n)); pi != want {
+			t.Errorf("PiExact(%d) == %d, want %d", n, pi, want)
+		}
+	}
+}
+
+func TestPiRange(t *testing.T) {
+	cases := []struct {
+		lo, hi int
+		want   int
+	}{
+		{-10, -1, 0},
+		{-10, 2, 1},
+		{2, 2, 1},
+		{3, 2, 0},
+		{0, 10, 4},
+		{10, 0, 0},
+		{14, 16, 0},
+		{1000000, 1000099, 6},
+		{1000000000, 1000000100, 7},
+	}
+	for _, c := range cases {
+		if pi := primes.PiRange(c.lo, c.hi); pi != c.want {
+			t.Errorf("PiRange(%d,%d) == %d, want %d", c.lo, c.hi, pi, c.want)
+		}
+	}
+
+	// Check against the primes generated by Sieve
+	ps := primes.Sieve(200000)
+	for lo := 0; lo < 100000; lo += 9973 {
+		for hi := lo; hi < 200000; hi += 7919 {
+			want := 0
+			for _, p := range ps {
+				if lo <= p && p <= hi {
+					want++
+				}
+			}
+			if pi := primes.PiRange(lo, hi); pi != want {
+				t.Errorf("PiRange(%d,%d) == %d, want %d", lo, hi, pi, want)
+			}
+		}
+	}
+}
+
+func TestCountRange(t *testing.T) {
+	cases := []struct {
+		lo, hi int
+		want   int
+	}{
+		{-10, -1, 0},
+		{-10, 3, 1},
+		{2, 2, 0},
+		{2, 3, 1},
+		{3, 2, 0},
+		{0, 10, 4},
+		{0, 11, 4},
+		{0, 12, 5},
+		{14, 17, 0},
+		{1000000, 1000100, 6},
+	}
+	for _, c := range cases {
+		if n := primes.CountRange(c.lo, c.hi); n != c.want {
+			t.Errorf("CountRange(%d,%d) == %d, want %d", c.lo, c.hi, n, c.want)
+		}
+	}
+
+	// Check against SieveRange
+	for lo := -100; lo < 100000; lo += 9973 {
+		for hi := lo - 1; hi < 300000; hi += 29989 {
+			if n, want := primes.CountRange(lo, hi), len(primes.SieveRange(lo, hi-1)); n != want {
+				t.Errorf("CountRange(%d,%d) == %d, want %d", lo, hi, n, want)
+			}
+		}
+	}
+}
+
+func TestSumRange(t *testing.T) {
+	cases := []struct {
+		lo, hi int
+		want   int64
+	}{
+		{-10, -1, 0},
+		{-10, 2, 2},
+		{2, 2, 2},
+		{3, 2, 0},
+		{0, 10, 17},
+		{14, 16, 0},
+		{0, 2000000, 142913828922},
+		{1000000, 1000099, 6000292},
+		{1000000000, 1000000100, 7000000347},
+	}
+	for _, c := range cases {
+		if sum := primes.SumRange(c.lo, c.hi); sum != c.want {
+			t.Errorf("SumRange(%d,%d) == %d, want %d", c.lo, c.hi, sum, c.want)
+		}
+	}
+
+	// Check against the sum of the primes listed by SieveRange
+	for lo := -100; lo < 100000; lo += 9973 {
+		for hi := lo - 1; hi < 300000; hi += 29989 {
+			want := int64(0)
+			for _, p := range primes.SieveRange(lo, hi) {
+				want += int64(p)
+			}
+			if sum := primes.SumRange(lo, hi); sum != want {
+				t.Errorf("SumRange(%d,%d) == %d, want %d", lo, hi, sum, want)
+			}
+		}
+	}
+}
+
+func TestCountPrimesInAP(t *testing.T) {
+	cases := []struct {
+		r, d, n int
+		want    int
+	}{
+		{1, 0, 100, 0},
+		{1, -4, 100, 0},
+		{0, 1, 100, 25},
+		{1, 2, 100, 24},
+		{1, 4, 100, 11},
+		{3, 4, 100, 13},
+		{-1, 4, 100, 13},
+		{7, 4, 100, 13},
+		{1, 4, 1000000, 39175},
+		{3, 4, 1000000, 39322},
+		// Non-coprime progressions contain at most one prime
+		{0, 2, 100, 1},
+		{2, 4, 100, 1},
+		{2, 4, 1, 0},
+		{4, 4, 100, 0},
+		{3, 6, 100, 1},
+		{9, 6, 100, 1},
+		{15, 10, 100, 1},
+		{6, 9, 100, 0},
+		{6, 8, 100, 0},
+	}
+	for _, c := range cases {
+		if count := primes.CountPrimesInAP(c.r, c.d, c.n); count != c.want {
+			t.Errorf("CountPrimesInAP(%d,%d,%d) == %d, want %d", c.r, c.d, c.n, count, c.want)
+		}
+	}
+
+	// Check against the primes generated by Sieve
+	const n = 100000
+	ps := primes.Sieve(n)
+	for _, d := range []int{1, 2, 3, 10, 30, 97} {
+		for r := 0; r < d; r++ {
+			want := 0
+			for _, p := range ps {
+				if p%d == r {
+					want++
+				}
+			}
+			if count := primes.CountPrimesInAP(r, d, n); count != want {
+				t.Errorf("CountPrimesInAP(%d,%d,%d) == %d, want %d", r, d, n, count, want)
+			}
+		}
+	}
+}
+
+func TestPrimeIndexRange(t *testing.T) {
+	// See https://oeis.org/A000040
+	cases := []struct {
+		k                int
+		prime, nextPrime int
+	}{
+		{-1, 0, 0},
+		{0, 0, 0},
+		{1, 2, 3},
+		{2, 3, 5},
+		{25, 97, 101},
+		{1228, 9967, 9973},
+		{1229, 9973, 10007},
+		{1230, 10007, 10009},
+		{10000, 104729, 104743},
+		{100000, 1299709, 1299721},
+		{1000000, 15485863, 15485867},
+	}
+	for _, c := range cases {
+		p, q := primes.PrimeIndexRange(c.k)
+		if p != c.prime || q != c.nextPrime {
+			t.Errorf("PrimeIndexRange(%d) == (%d,%d), want (%d,%d)", c.k, p, q, c.prime, c.nextPrime)
+		}
+	}
+
+	// Check against Sieve and PiExact
+	ps := primes.Sieve(200000)
+	for k := 1; k < len(ps); k += 997 {
+		p, q := primes.PrimeIndexRange(k)
+		if p != ps[k-1] || q != ps[k] {
+			t.Errorf("PrimeIndexRange(%d) == (%d,%d), want (%d,%d)", k, p, q, ps[k-1], ps[k])
+		}
+		if pi := primes.PiExact(q - 1); pi != k {
+			t.Errorf("PiExact(%d) == %d, want %d", q-1, pi, k)
+		}
+	}
+}
+
+func TestPrimesPerBitLength(t *testing.T) {
+	for _, maxBits := range []int{-1, 0, 1} {
+		if counts := primes.PrimesPerBitLength(maxBits); len(counts) != 0 {
+			t.Errorf("PrimesPerBitLength(%d) == %v, want []", maxBits, counts)
+		}
+	}
+
+	// Check against the bit lengths of the primes generated by Sieve
+	const maxBits = 20
+	want := make([]int, maxBits-1)
+	for _, p := range baselineSieve(1<<maxBits - 1) {
+		want[bits.Len(uint(p))-2]++
+	}
+	counts := primes.PrimesPerBitLength(maxBits)
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("PrimesPerBitLength(%d) == %v, want %v", maxBits, counts, want)
+	}
+}
+
+func TestSieveRange(t *testing.T) {
+	cases := []struct {
+		lo, hi int
+		want   []int
+	}{
+		{-10, -1, []int{}},
+		{-10, 2, []int{2}},
+		{2, 2, []int{2}},
+		{3, 2, []int{}},
+		{0, 10, []int{2, 3, 5, 7}},
+		{10, 0, []int{}},
+		{14, 16, []int{}},
+		{1000000, 1000099, []int{1000003, 1000033, 1000037, 1000039, 1000081, 1000099}},
+	}
+	for _, c := range cases {
+		if ps := primes.SieveRange(c.lo, c.hi); !reflect.DeepEqual(ps, c.want) {
+			t.Errorf("SieveRange(%d,%d) == %v, want %v", c.lo, c.hi, ps, c.want)
+		}
+	}
+
+	// Check against the primes generated by Sieve
+	all := primes.Sieve(300000)
+	for lo := 0; lo < 100000; lo += 9973 {
+		for hi := lo; hi < 300000; hi += 29989 {
+			want := []int{}
+			for _, p := range all {
+				if lo <= p && p <= hi {
+					want = append(want, p)
+				}
+			}
+			if ps := primes.SieveRange(lo, hi); !reflect.DeepEqual(ps, want) {
+				t.Errorf("SieveRange(%d,%d) returned %d primes, want %d", lo, hi, len(ps), len(want))
+			}
+		}
+	}
+}
+
+func TestSieve64(t *testing.T) {
+	for _, n := range []int{-1, 0, 1, 2, 3, 100, 10000, 1000000} {
+		ps := primes.Sieve64(int64(n))
+		want := primes.Sieve(n)
+		if len(ps) != len(want) {
+			t.Errorf("|Sieve64(%d)| == %d, want %d", n, len(ps), len(want))
+			continue
+		}
+		for i, p := range want {
+			if ps[i] != int64(p) {
+				t.Errorf("Sieve64(%d)[%d] == %d, want %d", n, i, ps[i], p)
+				break
+			}
+		}
+	}
+}
+
+func TestSieveRange64(t *testing.T) {
+	// Check ranges around the largest int32 and above it against IsPrimeMR
+	ranges := [][2]int64{
+		{math.MaxInt32 - 1000, math.MaxInt32 + 1000},
+		{1 << 33, 1<<33 + 100000},
+		{1e12, 1e12 + 10000},
+		{1e15, 1e15 + 10000},
+	}
+	for _, r := range ranges {
+		lo, hi := r[0], r[1]
+		ps := primes.SieveRange64(lo, hi)
+		want := []int64{}
+		for n := lo; n <= hi; n++ {
+			if primes.IsPrimeMR(n) {
+				want = append(want, n)
+			}
+		}
+		if !reflect.DeepEqual(ps, want) {
+			t.Errorf("SieveRange64(%d,%d) == %v, want %v", lo, hi, ps, want)
+		}
+	}
+}
+
+func TestSmallestPrimeFactorsSegment(t *testing.T) {
+	cases := []struct {
+		lo, hi int
+		want   []int
+	}{
+		{5, 5, []int{}},
+		{10, 0, []int{}},
+		{-3, 3, []int{0, 0, 0, 0, 0, 2}},
+		{0, 12, []int{0, 0, 2, 3, 2, 5, 2, 7, 2, 3, 2, 11}},
+		{1000000, 1000004, []int{2, 101, 2, 1000003}},
+	}
+	for _, c := range cases {
+		if spf := primes.SmallestPrimeFactorsSegment(c.lo, c.hi); !reflect.DeepEqual(spf, c.want) {
+			t.Errorf("SmallestPrimeFactorsSegment(%d,%d) == %v, want %v", c.lo, c.hi, spf, c.want)
+		}
+	}
+
+	// Check against the smallest prime factor of each number
+	ranges := []struct {
+		lo, hi int
+	}{
+		{0, 5000},
+		{9000, 11000},
+		{1000000, 1010000},
+		{1 << 30, 1<<30 + 3000},
+	}
+	for _, r := range ranges {
+		spf := primes.SmallestPrimeFactorsSegment(r.lo, r.hi)
+		for i, p := range spf {
+			n := r.lo + i
+			if want := primes.SmallestPrimeFactor(n); p != want {
+				t.Errorf("SmallestPrimeFactorsSegment(%d,%d)[%d] == %d, want %d", r.lo, r.hi, i, p, want)
+			}
+		}
+	}
+}
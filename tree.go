@@ -0,0 +1,92 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+import (
+	"strconv"
+	"strings"
+)
+
+// TreeNode is a node of a factorization tree (see FactorTree).
+// Leaves hold prime numbers and have no children; every other node has
+// exactly two children holding the smallest prime factor of its value
+// and the corresponding cofactor.
+type TreeNode struct {
+	Value    int
+	Children []*TreeNode
+}
+
+// FactorTree returns the factorization tree of n: its root holds n and every
+// composite node is split into its smallest prime factor and the cofactor,
+// so that the leaves of the tree, read left to right, are the prime factors
+// of n in increasing order.
+// If n is less than 2, it returns nil.
+// See https://en.wikipedia.org/wiki/Integer_factorization for details.
+func FactorTree(n int) *TreeNode {
+	if n < 2 {
+		return nil
+	}
+	t := &TreeNode{Value: n}
+	if p := SmallestPrimeFactor(n); p < n {
+		t.Children = []*TreeNode{{Value: p}, FactorTree(n / p)}
+	}
+	return t
+}
+
+// Leaves returns the values held by the leaves of the tree rooted at t,
+// from left to right.
+func (t *TreeNode) Leaves() []int {
+	if t == nil {
+		return []int{}
+	}
+	if len(t.Children) == 0 {
+		return []int{t.Value}
+	}
+	ls := []int{}
+	for _, c := range t.Children {
+		ls = append(ls, c.Leaves()...)
+	}
+	return ls
+}
+
+// String returns a multi-line representation of the tree rooted at t,
+// with one node per line and every child indented by two spaces relative
+// to its parent.
+func (t *TreeNode) String() string {
+	var b strings.Builder
+	t.write(&b, 0)
+	return b.String()
+}
+
+// write writes the tree rooted at t to b, indented by depth levels.
+func (t *TreeNode) write(b *strings.Builder, depth int) {
+	if t == nil {
+		return
+	}
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString(strconv.Itoa(t.Value))
+	b.WriteByte('\n')
+	for _, c := range t.Children {
+		c.write(b, depth+1)
+	}
+}
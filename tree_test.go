@@ -0,0 +1,94 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestFactorTree(t *testing.T) {
+	for _, n := range []int{-1, 0, 1} {
+		if tree := primes.FactorTree(n); tree != nil {
+			t.Errorf("FactorTree(%d) == %v, want nil", n, tree)
+		}
+	}
+
+	// checkNode verifies that every composite node splits into two
+	// children whose product is the value of the node
+	var checkNode func(n int, tree *primes.TreeNode)
+	checkNode = func(n int, tree *primes.TreeNode) {
+		switch len(tree.Children) {
+		case 0:
+			if !primes.IsPrime(tree.Value) {
+				t.Errorf("FactorTree(%d): leaf %d is not prime", n, tree.Value)
+			}
+		case 2:
+			l, r := tree.Children[0], tree.Children[1]
+			if l.Value*r.Value != tree.Value {
+				t.Errorf("FactorTree(%d): %d has children %d and %d", n, tree.Value, l.Value, r.Value)
+			}
+			checkNode(n, l)
+			checkNode(n, r)
+		default:
+			t.Errorf("FactorTree(%d): %d has %d children", n, tree.Value, len(tree.Children))
+		}
+	}
+
+	ns := []int{2, 12, 360, 9973, 1 << 20, 2 * 3 * 5 * 7 * 11 * 13, 9973 * 10007}
+	for n := 2; n <= 1000; n++ {
+		ns = append(ns, n)
+	}
+	for _, n := range ns {
+		tree := primes.FactorTree(n)
+		if tree.Value != n {
+			t.Errorf("FactorTree(%d).Value == %d, want %d", n, tree.Value, n)
+		}
+		checkNode(n, tree)
+		// The leaves must be prime and multiply back to n
+		prod := 1
+		for _, p := range tree.Leaves() {
+			prod *= p
+			if !primes.IsPrime(p) {
+				t.Errorf("FactorTree(%d).Leaves() contains %d, which is not prime", n, p)
+			}
+		}
+		if prod != n {
+			t.Errorf("FactorTree(%d).Leaves() == %v, whose product is %d", n, tree.Leaves(), prod)
+		}
+	}
+}
+
+func TestTreeNodeString(t *testing.T) {
+	want := "12\n  2\n  6\n    2\n    3\n"
+	if s := primes.FactorTree(12).String(); s != want {
+		t.Errorf("FactorTree(12).String() == %q, want %q", s, want)
+	}
+	if s := primes.FactorTree(7).String(); s != "7\n" {
+		t.Errorf("FactorTree(7).String() == %q, want %q", s, "7\n")
+	}
+	if s := primes.FactorTree(1).String(); s != "" {
+		t.Errorf("FactorTree(1).String() == %q, want %q", s, "")
+	}
+}
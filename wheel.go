@@ -0,0 +1,103 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes
+
+// wheel30 lists the residues modulo 30 of the numbers coprime to 30, that
+// is the spokes of a 2*3*5 wheel
+var wheel30 = [8]int{1, 7, 11, 13, 17, 19, 23, 29}
+
+// wheel30Gaps holds the distance from each spoke of the wheel to the next
+// one (the last one wraps around to 31)
+var wheel30Gaps = [8]int{6, 4, 2, 4, 2, 4, 6, 2}
+
+// wheel30Bits maps each residue modulo 30 that is a spoke of the wheel to
+// its bit in a SieveWheel block
+var wheel30Bits = func() (bs [30]uint8) {
+	for j, r := range wheel30 {
+		bs[r] = 1 << j
+	}
+	return
+}()
+
+// SieveWheel returns a list of the prime numbers less than or equal to n,
+// the same list returned by Sieve(n), using a sieve of Eratosthenes over
+// a 2*3*5 wheel.
+// Only the 8 numbers in every 30 that are coprime to 30 are candidates, so
+// the sieve skips 73% of the numbers where Sieve skips only the even ones;
+// each block of 30 numbers is stored in a single byte with one bit per
+// candidate, so the sieve takes n/30 bytes of memory instead of the n/2
+// taken by Sieve, and the multiples of each prime p are marked off only
+// when they are coprime to 30, by stepping through the spokes of the wheel.
+// See https://en.wikipedia.org/wiki/Wheel_factorization for details.
+func SieveWheel(n int) []int {
+	if n < 7 {
+		ps := []int{}
+		for _, p := range []int{2, 3, 5} {
+			if p <= n {
+				ps = append(ps, p)
+			}
+		}
+		return ps
+	}
+	// Bit j of composite[k] is set if 30*k+wheel30[j] is composite
+	composite := make([]uint8, n/30+1)
+	// 1 is not prime
+	composite[0] = 1
+sieve:
+	for k := 0; ; k++ {
+		for j, r := range wheel30 {
+			p := 30*k + r
+			if p > n/p {
+				break sieve
+			}
+			if composite[k]&(1<<j) != 0 {
+				continue
+			}
+			// Mark off p*q for every q >= p on the wheel, starting with
+			// q == p on spoke j
+			for m, i := p*p, j; ; i = (i + 1) % 8 {
+				composite[m/30] |= wheel30Bits[m%30]
+				step := p * wheel30Gaps[i]
+				if m > n-step {
+					break
+				}
+				m += step
+			}
+		}
+	}
+	pi, _ := Pi(n)
+	ps := make([]int, 3, pi)
+	ps[0], ps[1], ps[2] = 2, 3, 5
+	for k, c := range composite {
+		for j, r := range wheel30 {
+			if c&(1<<j) == 0 {
+				p := 30*k + r
+				if p > n {
+					return ps
+				}
+				ps = append(ps, p)
+			}
+		}
+	}
+	return ps
+}
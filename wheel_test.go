@@ -0,0 +1,44 @@
+// The MIT License (MIT)
+//
+// Copyright (c) 2015 Filippo Tampieri
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package primes_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fxtlabs/primes"
+)
+
+func TestSieveWheel(t *testing.T) {
+	// Check around the spokes of the wheel and the first few squares
+	for n := -1; n <= 1000; n++ {
+		if ps, want := primes.SieveWheel(n), primes.Sieve(n); !reflect.DeepEqual(ps, want) {
+			t.Errorf("SieveWheel(%d) == %v, want %v", n, ps, want)
+		}
+	}
+	for _, n := range []int{9973, 10000, 30029, 30030, 1000000, 10000019} {
+		if ps, want := primes.SieveWheel(n), primes.Sieve(n); !reflect.DeepEqual(ps, want) {
+			t.Errorf("SieveWheel(%d) returned %d primes, want %d", n, len(ps), len(want))
+		}
+	}
+}